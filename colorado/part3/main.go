@@ -14,9 +14,11 @@ func main() {
 	var (
 		refFile string
 		outFile string
+		compare bool
 	)
 	flag.StringVar(&refFile, "ref", "", "File containing desired solution")
 	flag.StringVar(&outFile, "out", "", "File to contain output")
+	flag.BoolVar(&compare, "compare", false, "Check that composite and Big-M phase one agree with the auxiliary problem")
 
 	flag.Parse()
 	if flag.NArg() != 1 {
@@ -36,6 +38,7 @@ func main() {
 	fmt.Println("feas?", dict.Feas())
 	fmt.Println()
 
+	orig := dict
 	dict = lp.ToFeasDict(dict)
 	dict.Fprint(os.Stdout)
 	fmt.Println("feas?", dict.Feas())
@@ -59,6 +62,13 @@ func main() {
 
 	result := Solution{dict.Obj()}
 
+	if compare {
+		if err := comparePhaseOne(orig); err != nil {
+			log.Fatalln("fail:", err)
+		}
+		log.Print("phase one methods agree")
+	}
+
 	if refFile != "" {
 		// Load reference output.
 		var ref Solution
@@ -82,6 +92,31 @@ func main() {
 	}
 }
 
+// Solves the problem using each phase one method
+// and checks that the outcome is the same as using the auxiliary problem.
+func comparePhaseOne(dict *lp.Dict) error {
+	const eps = 1e-6
+
+	want, wantErr := lp.SolveOpts(dict, lp.Options{PhaseOne: lp.AuxPhaseOne})
+	methods := []struct {
+		name  string
+		phase lp.PhaseOne
+	}{{"composite", lp.CompositePhaseOne}, {"Big-M", lp.BigMPhaseOne}}
+	for _, m := range methods {
+		got, err := lp.SolveOpts(dict, lp.Options{PhaseOne: m.phase})
+		if (err == nil) != (wantErr == nil) {
+			return fmt.Errorf("%s: got error %v, want %v", m.name, err, wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if math.Abs(got.Obj()-want.Obj()) >= eps {
+			return fmt.Errorf("%s: objective: got %g, want %g", m.name, got.Obj(), want.Obj())
+		}
+	}
+	return nil
+}
+
 func check(result, ref Solution) error {
 	const eps = 1e-6
 
@@ -1,11 +1,11 @@
-./part3 -ref=unitTests/idict1.out unitTests/idict1 > /dev/null && \
-./part3 -ref=unitTests/idict2.out unitTests/idict2 > /dev/null && \
-./part3 -ref=unitTests/idict3.out unitTests/idict3 > /dev/null && \
-./part3 -ref=unitTests/idict4.out unitTests/idict4 > /dev/null && \
-./part3 -ref=unitTests/idict5.out unitTests/idict5 > /dev/null && \
-./part3 -ref=unitTests/idict6.out unitTests/idict6 > /dev/null && \
-./part3 -ref=unitTests/idict7.out unitTests/idict7 > /dev/null && \
-./part3 -ref=unitTests/idict8.out unitTests/idict8 > /dev/null && \
-./part3 -ref=unitTests/idict9.out unitTests/idict9 > /dev/null && \
-./part3 -ref=unitTests/idict10.out unitTests/idict10 > /dev/null && \
+./part3 -compare -ref=unitTests/idict1.out unitTests/idict1 > /dev/null && \
+./part3 -compare -ref=unitTests/idict2.out unitTests/idict2 > /dev/null && \
+./part3 -compare -ref=unitTests/idict3.out unitTests/idict3 > /dev/null && \
+./part3 -compare -ref=unitTests/idict4.out unitTests/idict4 > /dev/null && \
+./part3 -compare -ref=unitTests/idict5.out unitTests/idict5 > /dev/null && \
+./part3 -compare -ref=unitTests/idict6.out unitTests/idict6 > /dev/null && \
+./part3 -compare -ref=unitTests/idict7.out unitTests/idict7 > /dev/null && \
+./part3 -compare -ref=unitTests/idict8.out unitTests/idict8 > /dev/null && \
+./part3 -compare -ref=unitTests/idict9.out unitTests/idict9 > /dev/null && \
+./part3 -compare -ref=unitTests/idict10.out unitTests/idict10 > /dev/null && \
 echo "all tests passed"
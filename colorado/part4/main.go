@@ -64,8 +64,14 @@ func solve(dict *lp.Dict, epsInt float64) Solution {
 	if !dict.Feas() {
 		log.Println("init. dict. not feasible: solve feas. problem")
 		// Solve the feasibility problem.
-		var infeas bool
-		dict, infeas = lp.SolveFeas(dict)
+		var (
+			infeas bool
+			err    error
+		)
+		dict, infeas, err = lp.SolveFeas(dict)
+		if err != nil {
+			log.Fatalln(err)
+		}
 		if infeas {
 			// Continuous relaxation is infeasible,
 			// therefore integer problem is infeasible.
@@ -77,8 +83,14 @@ func solve(dict *lp.Dict, epsInt float64) Solution {
 	}
 
 	log.Println("solve")
-	var unbnd bool
-	dict, unbnd = lp.PivotToFinal(dict)
+	var (
+		unbnd bool
+		err   error
+	)
+	dict, unbnd, err = lp.PivotToFinal(dict)
+	if err != nil {
+		log.Fatalln(err)
+	}
 	if unbnd {
 		// Relaxation became unbounded.
 		log.Println("primal is unbounded")
@@ -99,7 +111,10 @@ func solve(dict *lp.Dict, epsInt float64) Solution {
 
 		log.Println("solve")
 		var unbnd bool
-		dict, unbnd = lp.PivotToFinal(dict)
+		dict, unbnd, err = lp.PivotToFinal(dict)
+		if err != nil {
+			log.Fatalln(err)
+		}
 		if unbnd {
 			// Dual of relaxation became unbounded.
 			log.Println("dual is unbounded (primal infeasible)")
@@ -115,7 +130,7 @@ func solve(dict *lp.Dict, epsInt float64) Solution {
 		//	// Find a feasible solution.
 		//	var infeas bool
 		//	log.Println("solve feasibility problem")
-		//	dict, infeas = lp.SolveFeas(dict)
+		//	dict, infeas, err = lp.SolveFeas(dict)
 		//	if infeas {
 		//		// Continuous relaxation is infeasible,
 		//		// therefore integer problem is infeasible.
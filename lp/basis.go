@@ -0,0 +1,68 @@
+package lp
+
+import "math"
+
+// Basis is a partition of the labels of a dictionary
+// into basic and non-basic variables.
+// The basis of a final dictionary can be used to warm start
+// the solution of a similar problem (see Options).
+type Basis struct {
+	Basic    []int
+	NonBasic []int
+}
+
+// Basis returns a copy of the labels of the basic and non-basic variables.
+func (dict *Dict) Basis() *Basis {
+	return &Basis{
+		Basic:    append([]int(nil), dict.Basic...),
+		NonBasic: append([]int(nil), dict.NonBasic...),
+	}
+}
+
+// ToBasis returns an equivalent dictionary in which
+// the variables of the basis are basic.
+// The dictionary is factorized by pivoting each variable of the basis
+// which is non-basic into the basic set
+// in place of a variable which is not in the basis,
+// choosing the largest available coefficient.
+//
+// Labels of the basis which are not in the dictionary are ignored.
+// If the basis is singular for the dictionary,
+// some variables of the basis remain non-basic.
+// The returned dictionary may be neither primal nor dual feasible.
+func (dict *Dict) ToBasis(b *Basis) *Dict {
+	return dict.ToBasisEps(b, DefaultEps)
+}
+
+func (dict *Dict) ToBasisEps(b *Basis, eps float64) *Dict {
+	want := make(map[int]bool, len(b.Basic))
+	for _, lbl := range b.Basic {
+		want[lbl] = true
+	}
+	for _, lbl := range b.Basic {
+		enter, found := find(lbl, dict.NonBasic)
+		if !found {
+			continue
+		}
+		// Find the row with the largest coefficient
+		// whose basic variable should be non-basic.
+		var (
+			leave = -1
+			max   float64
+		)
+		for i, other := range dict.Basic {
+			if want[other] {
+				continue
+			}
+			if a := math.Abs(dict.A[i][enter]); a > eps && a > max {
+				leave, max = i, a
+			}
+		}
+		if leave < 0 {
+			// Basis is singular.
+			continue
+		}
+		dict = dict.Pivot(enter, leave)
+	}
+	return dict
+}
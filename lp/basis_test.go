@@ -0,0 +1,102 @@
+package lp_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/jvlmdr/golp/lp"
+)
+
+func ExampleOptions_basis() {
+	// max x + 2 y s.t. -x + y <= 1, 3x + 2y <= 12, 2x + 3y <= 12
+	dict := lp.NewDict(3, 2)
+	dict.NonBasic = []int{0, 1}
+	dict.Basic = []int{2, 3, 4}
+	dict.C = []float64{1, 2}
+	dict.A = [][]float64{{1, -1}, {-3, -2}, {-2, -3}}
+	dict.B = []float64{1, 12, 12}
+	final, err := lp.Solve(dict)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+	basis := final.Basis()
+	fmt.Printf("%.6g at %.6g, basis %v\n", final.Obj(), final.Soln()[:2], basis.Basic)
+
+	// Demand changes: 2x + 3y <= 9.
+	// The basis is no longer primal feasible but remains dual feasible.
+	dict.B[2] = 9
+	final, err = lp.SolveOpts(dict, lp.Options{Basis: basis})
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+	fmt.Printf("%.6g at %.6g\n", final.Obj(), final.Soln()[:2])
+	// Output:
+	// 7.4 at [1.8 2.8], basis [3 0 1]
+	// 5.6 at [1.2 2.2]
+}
+
+// Returns a random problem which is feasible at zero and bounded.
+func randBoundedDict(r *rand.Rand, m, n int) *lp.Dict {
+	dict := lp.NewDict(m+n, n)
+	for j := range dict.NonBasic {
+		dict.NonBasic[j] = j
+		dict.C[j] = r.NormFloat64()
+	}
+	for i := range dict.Basic {
+		dict.Basic[i] = n + i
+		if i < m {
+			for j := range dict.NonBasic {
+				dict.A[i][j] = r.NormFloat64()
+			}
+			dict.B[i] = r.Float64()
+		} else {
+			// Upper bound on each variable.
+			dict.A[i][i-m] = -1
+			dict.B[i] = 1 + r.Float64()
+		}
+	}
+	return dict
+}
+
+func TestSolveOpts_basis(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 200; trial++ {
+		dict := randBoundedDict(r, 1+r.Intn(5), 1+r.Intn(5))
+		final, err := lp.Solve(dict)
+		if err != nil {
+			t.Fatal(err)
+		}
+		basis := final.Basis()
+		// The basis of the final dictionary gives the final dictionary.
+		if d := dict.ToBasis(basis); !d.Feas() || !d.Dual().Feas() {
+			t.Fatalf("trial %d: factorized dictionary is not final", trial)
+		}
+
+		// Modify the constants or the objective.
+		mod := dict.Clone()
+		for i := range mod.B {
+			mod.B[i] += 0.5 * r.NormFloat64()
+		}
+		if r.Intn(2) == 0 {
+			mod = dict.Clone()
+			for j := range mod.C {
+				mod.C[j] += 0.5 * r.NormFloat64()
+			}
+		}
+		want, wantErr := lp.Solve(mod)
+		got, err := lp.SolveOpts(mod, lp.Options{Basis: basis})
+		if (err == nil) != (wantErr == nil) {
+			t.Fatalf("trial %d: got error %v, want %v", trial, err, wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if math.Abs(got.Obj()-want.Obj()) > 1e-9 {
+			t.Errorf("trial %d: got objective %g, want %g", trial, got.Obj(), want.Obj())
+		}
+	}
+}
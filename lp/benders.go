@@ -0,0 +1,248 @@
+package lp
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Benders describes a two-stage linear program
+//	max {master objective + Q(x)}
+// subject to the constraints of the master dictionary,
+// where Q(x) is the optimal value of the subproblem
+// in which the constants of the dictionary Sub are
+//	Sub.B[i] + sum_k T[i][k] x[Vars[k]]
+// and x is the solution of the master problem.
+type Benders struct {
+	Master *Dict
+	Sub    *Dict
+	// Labels of the variables of the master problem in the subproblem.
+	Vars []int
+	T    [][]float64
+}
+
+// BendersOptions controls Benders decomposition.
+type BendersOptions struct {
+	// Tolerance for feasibility and pivoting.
+	// If zero, DefaultEps is used.
+	Eps float64
+	// Upper bound on the value of the subproblem,
+	// which bounds the master problem before any optimality cuts are added.
+	// If zero, 1e6 is used.
+	Bound float64
+	// The method stops when the upper bound exceeds
+	// the best solution by at most Gap.
+	// If zero, 1e-6 is used.
+	Gap float64
+	// Maximum number of iterations.
+	// If zero, there is no limit.
+	MaxIter int
+}
+
+// BendersResult is the outcome of Benders decomposition.
+type BendersResult struct {
+	// Best solution of the master and subproblem,
+	// in increasing order of label like Soln.
+	// Nil if no feasible solution was found.
+	X, Y []float64
+	// Objective value of the best solution.
+	Obj float64
+	// Upper bound on the objective of any solution.
+	Bound float64
+	// Number of times the master problem was solved.
+	Iter int
+	// Number of optimality and feasibility cuts added to the master problem.
+	OptCuts  int
+	FeasCuts int
+}
+
+// SolveBenders solves a two-stage linear program using Benders decomposition.
+//
+// The value of the subproblem is represented in the master problem
+// by a variable theta <= Bound which is added to its objective.
+// At each iteration, the master problem is solved and gives an upper bound.
+// The subproblem is then solved for the master solution x.
+// If it is feasible, this gives a solution
+// and the duals u of its final dictionary give the optimality cut
+//	theta <= Sub.D + sum_i u[i] (Sub.B[i] + sum_k T[i][k] x[Vars[k]]).
+// If it is infeasible, the dual dictionary (see Dual) is unbounded
+// and its ray u gives the feasibility cut
+//	sum_i u[i] (Sub.B[i] + sum_k T[i][k] x[Vars[k]]) >= 0.
+// The cuts are added to the final dictionary of the master problem,
+// which is re-solved using the dual simplex method.
+//
+// Returns an error if Vars contains a label which is not in Master,
+// if the master problem is infeasible or unbounded,
+// if all master solutions are infeasible for the subproblem
+// or if the subproblem is unbounded.
+// If MaxIter is reached, the best solution found is returned without error.
+func SolveBenders(prob *Benders, opts BendersOptions) (*BendersResult, error) {
+	eps := opts.Eps
+	if eps == 0 {
+		eps = DefaultEps
+	}
+	bound := opts.Bound
+	if bound == 0 {
+		bound = 1e6
+	}
+	gap := opts.Gap
+	if gap == 0 {
+		gap = 1e-6
+	}
+	if err := prob.Master.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid master dictionary: %v", err)
+	}
+	if err := prob.Sub.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid subproblem dictionary: %v", err)
+	}
+	if len(prob.T) != len(prob.Sub.B) {
+		return nil, fmt.Errorf("T has %d rows, want %d constants of subproblem", len(prob.T), len(prob.Sub.B))
+	}
+	for i, row := range prob.T {
+		if len(row) != len(prob.Vars) {
+			return nil, fmt.Errorf("row %d of T has length %d, want %d variables", i, len(row), len(prob.Vars))
+		}
+	}
+
+	// The master problem is solved in terms of dense labels.
+	master, labels := prob.Master.dense()
+	vars := make([]int, len(prob.Vars))
+	for k, lbl := range prob.Vars {
+		vars[k] = sort.SearchInts(labels, lbl)
+		if vars[k] == len(labels) || labels[vars[k]] != lbl {
+			return nil, fmt.Errorf("variable %d is not in master dictionary", lbl)
+		}
+	}
+	p := len(labels)
+
+	// Add theta = bound - t to the objective of the master problem.
+	master, t, err := master.AddVar(-1, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	master.D += bound
+
+	res := &BendersResult{Obj: math.Inf(-1), Bound: math.Inf(1)}
+	master, err = solveOptsEps(master, Options{}, eps)
+	if err != nil {
+		return nil, fmt.Errorf("master problem: %v", err)
+	}
+	for opts.MaxIter == 0 || res.Iter < opts.MaxIter {
+		res.Iter++
+		vals := master.values()
+		theta := bound - vals[t]
+		res.Bound = math.Min(res.Bound, master.Obj())
+		x := make([]float64, len(vars))
+		for k, lbl := range vars {
+			x[k] = vals[lbl]
+		}
+
+		sub := prob.Sub.Clone()
+		for i, row := range prob.T {
+			for k, a := range row {
+				sub.B[i] += a * x[k]
+			}
+		}
+		var cut Cut
+		final, u, err := solveSubEps(sub, eps)
+		if err != nil {
+			return nil, err
+		}
+		if final != nil {
+			if obj := master.Obj() - theta + final.Obj(); obj > res.Obj {
+				res.Obj = obj
+				res.X = vals[:p]
+				res.Y = final.Soln()
+			}
+			if res.Bound-res.Obj <= gap {
+				break
+			}
+			// bound - t <= D + sum_i u[i] (B[i] + T x)
+			cut.Labels = append(cut.Labels, t)
+			cut.Coeffs = append(cut.Coeffs, 1)
+			cut.Const = prob.Sub.D - bound
+			res.OptCuts++
+		} else {
+			// sum_i u[i] (B[i] + T x) >= 0
+			res.FeasCuts++
+		}
+		for i, ui := range u {
+			cut.Const += ui * prob.Sub.B[i]
+		}
+		for k, lbl := range vars {
+			var a float64
+			for i, ui := range u {
+				a += ui * prob.T[i][k]
+			}
+			cut.Labels = append(cut.Labels, lbl)
+			cut.Coeffs = append(cut.Coeffs, a)
+		}
+
+		master = AddCuts(master, []Cut{cut})
+		var infeas bool
+		master, infeas = pivotToFinalDualEps(master, eps)
+		if infeas {
+			if res.X == nil {
+				return nil, errors.New("no master solution is feasible for subproblem")
+			}
+			// The best solution is optimal.
+			res.Bound = res.Obj
+			break
+		}
+	}
+	return res, nil
+}
+
+// Solves the subproblem.
+// If it is feasible, returns the final dictionary and the duals
+// of the constraints of the subproblem.
+// If it is infeasible, returns a nil dictionary and a ray of the dual
+// along which the dual objective decreases without bound.
+func solveSubEps(sub *Dict, eps float64) (final *Dict, u []float64, err error) {
+	m := len(sub.Basic)
+	u = make([]float64, m)
+	dict := sub
+	var infeas bool
+	if !dict.Feas() {
+		dict, infeas = solveFeasEps(dict, eps)
+	}
+	if !infeas {
+		var unbnd bool
+		final, unbnd = pivotToFinalEps(dict, eps)
+		if unbnd {
+			return nil, nil, errors.New("unbounded subproblem")
+		}
+		// The dual variables are labelled by their primal complements.
+		for i, lbl := range sub.Basic {
+			if j, found := find(lbl, final.NonBasic); found {
+				u[i] = -final.C[j]
+			}
+		}
+		return final, u, nil
+	}
+
+	// Primal is infeasible, therefore dual is unbounded (or infeasible).
+	dual := sub.Dual()
+	if !dual.Feas() {
+		dual, infeas = solveFeasEps(dual, eps)
+		if infeas {
+			return nil, nil, errors.New("subproblem is infeasible and its dual is infeasible")
+		}
+	}
+	dual, unbnd := pivotToFinalEps(dual, eps)
+	if !unbnd {
+		// Should not be possible.
+		return nil, nil, errors.New("subproblem is infeasible but its dual is bounded")
+	}
+	// Increase the entering variable without bound.
+	enter, _ := toEnterBland(dual, eps)
+	for i, lbl := range sub.Basic {
+		if dual.NonBasic[enter] == lbl {
+			u[i] = 1
+		} else if r, found := find(lbl, dual.Basic); found {
+			u[i] = dual.A[r][enter]
+		}
+	}
+	return nil, u, nil
+}
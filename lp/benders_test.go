@@ -0,0 +1,104 @@
+package lp_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/jvlmdr/golp/lp"
+)
+
+func ExampleSolveBenders() {
+	// Build capacity x <= 10 at unit cost 1,
+	// then sell y <= x at price 3, with demand y <= 6.
+	master := lp.NewDict(1, 1)
+	master.NonBasic = []int{0}
+	master.Basic = []int{1}
+	master.C = []float64{-1}
+	master.A[0], master.B[0] = []float64{-1}, 10
+
+	sub := lp.NewDict(2, 1)
+	sub.NonBasic = []int{0}
+	sub.Basic = []int{1, 2}
+	sub.C = []float64{3}
+	// x - y >= 0
+	// 6 - y >= 0
+	sub.A[0], sub.B[0] = []float64{-1}, 0
+	sub.A[1], sub.B[1] = []float64{-1}, 6
+
+	prob := &lp.Benders{
+		Master: master,
+		Sub:    sub,
+		Vars:   []int{0},
+		T:      [][]float64{{1}, {0}},
+	}
+	res, err := lp.SolveBenders(prob, lp.BendersOptions{})
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+	fmt.Printf("%.6g at x %.6g, y %.6g\n", res.Obj, res.X[0], res.Y[0])
+	// Output:
+	// 12 at x 6, y 6
+}
+
+func TestSolveBenders(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 200; trial++ {
+		m1, n1 := 1+r.Intn(3), 1+r.Intn(3)
+		m2, n2 := 1+r.Intn(3), 1+r.Intn(3)
+		master := randBoundedDict(r, m1, n1)
+		sub := randBoundedDict(r, m2, n2)
+		prob := &lp.Benders{Master: master, Sub: sub}
+		for j := 0; j < n1; j++ {
+			prob.Vars = append(prob.Vars, j)
+		}
+		prob.T = make([][]float64, m2+n2)
+		for i := range prob.T {
+			prob.T[i] = make([]float64, n1)
+			if i < m2 {
+				for k := range prob.T[i] {
+					prob.T[i][k] = r.NormFloat64()
+				}
+			}
+		}
+
+		// Solve the extensive form in which the labels of the subproblem
+		// follow those of the master problem.
+		off := n1 + m1 + n1
+		ext := lp.NewDict(len(master.Basic)+len(sub.Basic), n1+n2)
+		copy(ext.NonBasic, master.NonBasic)
+		copy(ext.Basic, master.Basic)
+		copy(ext.C, master.C)
+		for i := range master.A {
+			copy(ext.A[i], master.A[i])
+			ext.B[i] = master.B[i]
+		}
+		for j, lbl := range sub.NonBasic {
+			ext.NonBasic[n1+j] = off + lbl
+			ext.C[n1+j] = sub.C[j]
+		}
+		for i := range sub.A {
+			k := len(master.Basic) + i
+			ext.Basic[k] = off + sub.Basic[i]
+			copy(ext.A[k], prob.T[i])
+			copy(ext.A[k][n1:], sub.A[i])
+			ext.B[k] = sub.B[i]
+		}
+		ext.D = master.D + sub.D
+		want, wantErr := lp.Solve(ext)
+
+		res, err := lp.SolveBenders(prob, lp.BendersOptions{Bound: 100})
+		if (err == nil) != (wantErr == nil) {
+			t.Fatalf("trial %d: got error %v, want %v", trial, err, wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if math.Abs(res.Obj-want.Obj()) > 1e-5 {
+			t.Errorf("trial %d: got %g, want %g (%d optimality, %d feasibility cuts)",
+				trial, res.Obj, want.Obj(), res.OptCuts, res.FeasCuts)
+		}
+	}
+}
@@ -85,16 +85,16 @@ func NextBlandEps(dict *Dict, eps float64) Pivot {
 
 // NextFeasBland returns the next pivot operation to perform according to Bland's rule
 // for a feasibility problem.
-// This treats the variable with label len(NonBasic)+len(Basic)-1 as a special variable
+// This treats the variable with the largest label
+// (the auxiliary variable added by ToFeasDict) as a special variable
 // which receives priority to leave the basic set.
 func NextFeasBland(dict *Dict) Pivot {
 	return NextFeasBlandEps(dict, DefaultEps)
 }
 
 func NextFeasBlandEps(dict *Dict, eps float64) Pivot {
-	m, n := len(dict.Basic), len(dict.NonBasic)
-	// First check if there is a variable with label m+n-1 in the basic set.
-	zero, found := find(m+n-1, dict.Basic)
+	// First check if the auxiliary variable is in the basic set.
+	zero, found := find(numVars(dict)-1, dict.Basic)
 	if found {
 		// If there is and it can leave the basic set, make this pivot.
 		enter, canLeave := toEnterFeasBland(dict, zero, eps)
@@ -0,0 +1,437 @@
+package lp
+
+import (
+	"container/heap"
+	"log"
+	"math"
+	"sync"
+	"time"
+)
+
+// State of the branch-and-cut search.
+// All fields below mu are shared between workers.
+type search struct {
+	// Original dictionary in terms of dense labels.
+	orig   *Dict
+	labels []int
+	opts   IntOptions
+	eps    float64
+	// Number of variables in the original dictionary.
+	p     int
+	start time.Time
+
+	mu   sync.Mutex
+	cond *sync.Cond
+	// Open nodes which have not been solved.
+	queue nodeQueue
+	// Bounds of nodes which are being solved.
+	active map[int]float64
+	// Number of nodes created.
+	count int
+	res   IntResult
+}
+
+// A node is an optimal dictionary of the relaxation
+// which has been restricted by branching.
+type node struct {
+	dict *Dict
+	seq  int
+}
+
+// Nodes with the greatest bound are solved first.
+// Ties are broken by the order of creation.
+type nodeQueue []node
+
+func (q nodeQueue) Len() int { return len(q) }
+
+func (q nodeQueue) Less(i, j int) bool {
+	if q[i].dict.Obj() != q[j].dict.Obj() {
+		return q[i].dict.Obj() > q[j].dict.Obj()
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q nodeQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *nodeQueue) Push(x interface{}) { *q = append(*q, x.(node)) }
+
+func (q *nodeQueue) Pop() interface{} {
+	n := len(*q)
+	x := (*q)[n-1]
+	*q = (*q)[:n-1]
+	return x
+}
+
+func newSearch(orig *Dict, labels []int, opts IntOptions, eps float64) *search {
+	s := &search{
+		orig:   orig,
+		labels: labels,
+		opts:   opts,
+		eps:    eps,
+		p:      numVars(orig),
+		start:  time.Now(),
+		active: make(map[int]float64),
+	}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+// The following methods must be called with the lock held.
+
+func (s *search) push(dict *Dict) {
+	heap.Push(&s.queue, node{dict, s.count})
+	s.count++
+}
+
+func (s *search) pop() node {
+	n := heap.Pop(&s.queue).(node)
+	s.res.Nodes++
+	s.active[n.seq] = n.dict.Obj()
+	return n
+}
+
+// Returns the greatest bound of all open and active nodes.
+func (s *search) bound() float64 {
+	bound := math.Inf(-1)
+	if len(s.queue) > 0 {
+		bound = s.queue[0].dict.Obj()
+	}
+	for _, b := range s.active {
+		bound = math.Max(bound, b)
+	}
+	if s.res.X != nil {
+		// Pruned nodes can not exceed the incumbent.
+		bound = math.Max(bound, s.res.Obj)
+	}
+	return bound
+}
+
+// Returns a copy of the result with the bound and gap
+// in terms of the original labels.
+func (s *search) result() *IntResult {
+	res := s.res
+	res.Dict = sparse(res.Dict, s.labels)
+	res.Bound = s.bound()
+	res.Gap = math.Inf(1)
+	if res.X != nil {
+		res.Gap = (res.Bound - res.Obj) / math.Max(math.Abs(res.Obj), 1)
+	}
+	return &res
+}
+
+// Returns true if a node with the given bound can not improve enough
+// on an incumbent with the given objective.
+func (s *search) prune(bound, obj float64) bool {
+	tol := math.Max(s.eps, math.Max(s.opts.AbsGap, s.opts.RelGap*math.Abs(obj)))
+	return bound <= obj+tol
+}
+
+// Returns true if the time limit has been reached.
+func (s *search) timeUp() bool {
+	return s.opts.TimeLimit > 0 && time.Since(s.start) >= s.opts.TimeLimit
+}
+
+// Returns true if a limit has been reached or the gap has been closed.
+func (s *search) stopped() bool {
+	if s.opts.NodeLimit > 0 && s.res.Nodes >= s.opts.NodeLimit {
+		return true
+	}
+	return s.timeUp() || s.res.X != nil && s.prune(s.bound(), s.res.Obj)
+}
+
+// Returns true if the search should stop.
+func (s *search) done() bool {
+	return len(s.queue) == 0 || s.stopped()
+}
+
+// Records x as the incumbent if it improves the objective.
+// The dictionary is nil if the solution was found by a heuristic.
+func (s *search) update(x []float64, obj float64, dict *Dict) {
+	if s.res.X != nil && obj <= s.res.Obj+s.eps {
+		return
+	}
+	s.res.X, s.res.Obj, s.res.Dict = x, obj, dict
+	if s.opts.Progress != nil {
+		s.opts.Progress(*s.result())
+	}
+}
+
+// Explores nodes using the configured number of workers.
+func (s *search) run() {
+	k := s.opts.Workers
+	if k < 1 {
+		k = 1
+	}
+	if s.opts.Deterministic {
+		s.runBatches(k)
+	} else {
+		s.runWorkers(k)
+	}
+}
+
+// Each worker takes the best open node whenever it is idle.
+func (s *search) runWorkers(k int) {
+	var wg sync.WaitGroup
+	for t := 0; t < k; t++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := newWorker(s, false)
+			for {
+				s.mu.Lock()
+				// Wait for other workers to create nodes.
+				for len(s.queue) == 0 && len(s.active) > 0 && !s.stopped() {
+					s.cond.Wait()
+				}
+				if s.done() {
+					s.cond.Broadcast()
+					s.mu.Unlock()
+					return
+				}
+				n := s.pop()
+				s.mu.Unlock()
+
+				children := w.solve(n)
+
+				s.mu.Lock()
+				delete(s.active, n.seq)
+				for _, child := range children {
+					s.push(child)
+				}
+				s.cond.Broadcast()
+				s.mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Nodes are solved in batches.
+// The workers in a batch see the same incumbent
+// and their results are merged in the order of the nodes,
+// therefore the search does not depend on the scheduling of goroutines.
+func (s *search) runBatches(k int) {
+	workers := make([]*worker, k)
+	for t := range workers {
+		workers[t] = newWorker(s, true)
+	}
+	for !s.done() {
+		var batch []node
+		for len(batch) < k && len(s.queue) > 0 {
+			if s.opts.NodeLimit > 0 && s.res.Nodes >= s.opts.NodeLimit {
+				break
+			}
+			batch = append(batch, s.pop())
+		}
+
+		children := make([][]*Dict, len(batch))
+		var wg sync.WaitGroup
+		for t, n := range batch {
+			w := workers[t]
+			w.reset(s.res.X != nil, s.res.Obj)
+			wg.Add(1)
+			go func(t int, n node) {
+				defer wg.Done()
+				children[t] = w.solve(n)
+			}(t, n)
+		}
+		wg.Wait()
+
+		for t, n := range batch {
+			w := workers[t]
+			s.res.CutRounds += w.cutRounds
+			for _, f := range w.found {
+				s.update(f.x, f.obj, f.dict)
+			}
+			delete(s.active, n.seq)
+			for _, child := range children[t] {
+				s.push(child)
+			}
+		}
+	}
+}
+
+// A worker solves nodes with its own copy of the original dictionary.
+// Node dictionaries are never modified, only replaced,
+// therefore workers can share them without copying.
+type worker struct {
+	s    *search
+	orig *Dict
+	// In deterministic mode, the worker only sees the incumbent
+	// from the start of the batch and records its solutions and cuts
+	// to be merged after the batch.
+	sync      bool
+	haveInc   bool
+	incObj    float64
+	found     []found
+	cutRounds int
+}
+
+// An integer solution found by a worker.
+type found struct {
+	x    []float64
+	obj  float64
+	dict *Dict
+}
+
+func newWorker(s *search, sync bool) *worker {
+	return &worker{s: s, orig: s.orig.Clone(), sync: sync}
+}
+
+// Prepares the worker for a batch with the given incumbent.
+func (w *worker) reset(haveInc bool, incObj float64) {
+	w.haveInc, w.incObj = haveInc, incObj
+	w.found = nil
+	w.cutRounds = 0
+}
+
+// Updates the bound of the node and returns true if it can be pruned.
+func (w *worker) prune(n node, bound float64) bool {
+	if w.sync {
+		return w.haveInc && w.s.prune(bound, w.incObj)
+	}
+	w.s.mu.Lock()
+	defer w.s.mu.Unlock()
+	w.s.active[n.seq] = bound
+	return w.s.res.X != nil && w.s.prune(bound, w.s.res.Obj)
+}
+
+// Records an integer solution.
+func (w *worker) update(x []float64, obj float64, dict *Dict) {
+	if w.sync {
+		if w.haveInc && obj <= w.incObj+w.s.eps {
+			return
+		}
+		w.haveInc, w.incObj = true, obj
+		w.found = append(w.found, found{x, obj, dict})
+		return
+	}
+	w.s.mu.Lock()
+	defer w.s.mu.Unlock()
+	w.s.update(x, obj, dict)
+}
+
+func (w *worker) addCutRound() {
+	if w.sync {
+		w.cutRounds++
+		return
+	}
+	w.s.mu.Lock()
+	defer w.s.mu.Unlock()
+	w.s.res.CutRounds++
+}
+
+// Runs the heuristics on the solution of the relaxation.
+func (w *worker) runHeuristics(relax *Dict) {
+	for _, h := range w.s.opts.Heuristics {
+		x, ok := h.Find(w.orig, relax, w.s.eps)
+		if !ok {
+			continue
+		}
+		_, obj := w.orig.point(x)
+		w.update(x, obj, nil)
+	}
+}
+
+// Adds cuts to the node until the relaxation is integer,
+// the node is pruned or the number of rounds is exhausted.
+// Returns the children of the node.
+func (w *worker) solve(n node) []*Dict {
+	s := w.s
+	dict := n.dict
+	for round := 0; ; round++ {
+		if w.prune(n, dict.Obj()) {
+			return nil
+		}
+		if dict.IsIntEps(s.eps) {
+			w.update(roundAll(dict.values()[:s.p]), dict.Obj(), dict)
+			return nil
+		}
+		w.runHeuristics(dict)
+		if w.prune(n, dict.Obj()) {
+			return nil
+		}
+		if s.opts.CutRounds < 0 || s.opts.CutRounds > 0 && round >= s.opts.CutRounds {
+			break
+		}
+		if s.timeUp() {
+			// Keep the node so that its bound is not lost.
+			return []*Dict{dict}
+		}
+		var infeas bool
+		dict, infeas = w.cutRound(dict)
+		if infeas {
+			return nil
+		}
+	}
+	return w.branch(dict)
+}
+
+// Adds Gomory cuts and the cuts found by any separators
+// and re-solves the relaxation.
+func (w *worker) cutRound(dict *Dict) (next *Dict, infeas bool) {
+	eps := w.s.eps
+	// Separate the current solution before adding Gomory cuts.
+	var cuts []Cut
+	for _, sep := range w.s.opts.Separators {
+		cuts = append(cuts, sep.Separate(dict, eps)...)
+	}
+	log.Println("add cutting-plane constraints")
+	dict = CutPlaneEps(dict, eps)
+	if len(cuts) > 0 {
+		log.Println("add separated cuts:", len(cuts))
+		dict = AddCuts(dict, cuts)
+	}
+	w.addCutRound()
+	next, infeas = pivotToFinalDualEps(dict, eps)
+	if infeas {
+		log.Println("unbounded in dual, infeasible in primal")
+		return nil, true
+	}
+	log.Println("objective:", next.Obj())
+	return next, false
+}
+
+// Splits the node into two children by bounding the most fractional variable,
+// with preference to variables of the original problem.
+// Returns the children whose relaxations are feasible.
+func (w *worker) branch(dict *Dict) []*Dict {
+	eps := w.s.eps
+	var (
+		found   bool
+		arg     int
+		max     float64
+		maxOrig bool
+	)
+	for i, lbl := range dict.Basic {
+		dist := math.Abs(distInt(dict.B[i]))
+		if dist <= eps {
+			continue
+		}
+		orig := lbl < w.s.p
+		if found && (maxOrig && !orig || maxOrig == orig && dist <= max) {
+			continue
+		}
+		found, arg, max, maxOrig = true, i, dist, orig
+	}
+	if !found {
+		return nil
+	}
+
+	lbl, val := dict.Basic[arg], dict.B[arg]
+	cuts := []Cut{
+		// x <= floor(val)
+		{Labels: []int{lbl}, Coeffs: []float64{-1}, Const: math.Floor(val)},
+		// x >= ceil(val)
+		{Labels: []int{lbl}, Coeffs: []float64{1}, Const: -math.Ceil(val)},
+	}
+	var children []*Dict
+	for _, cut := range cuts {
+		child, infeas := pivotToFinalDualEps(AddCuts(dict, []Cut{cut}), eps)
+		if infeas {
+			continue
+		}
+		children = append(children, child)
+	}
+	return children
+}
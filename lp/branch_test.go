@@ -0,0 +1,165 @@
+package lp_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/jvlmdr/golp/lp"
+)
+
+// Returns a random problem with non-negative integer coefficients
+// whose integer solutions are bounded by 10.
+func randIntDict(r *rand.Rand, m, n int) *lp.Dict {
+	dict := lp.NewDict(m, n)
+	for j := range dict.NonBasic {
+		dict.NonBasic[j] = j
+		dict.C[j] = float64(r.Intn(10))
+	}
+	for i := range dict.Basic {
+		dict.Basic[i] = n + i
+		for j := range dict.NonBasic {
+			dict.A[i][j] = -float64(1 + r.Intn(9))
+		}
+		dict.B[i] = float64(r.Intn(10 * n))
+	}
+	return dict
+}
+
+// Finds the optimal integer solution by enumeration.
+func bruteForceInt(dict *lp.Dict) float64 {
+	n := len(dict.NonBasic)
+	x := make([]float64, n)
+	best := math.Inf(-1)
+	var search func(j int)
+	search = func(j int) {
+		if j == n {
+			for i := range dict.Basic {
+				v := dict.B[i]
+				for k := range x {
+					v += dict.A[i][k] * x[k]
+				}
+				if v < 0 {
+					return
+				}
+			}
+			obj := dict.D
+			for k := range x {
+				obj += dict.C[k] * x[k]
+			}
+			best = math.Max(best, obj)
+			return
+		}
+		for v := 0; v <= 10; v++ {
+			x[j] = float64(v)
+			search(j + 1)
+		}
+	}
+	search(0)
+	return best
+}
+
+func TestSolveIntOpts_branch(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 50; trial++ {
+		dict := randIntDict(r, 3, 3)
+		want := bruteForceInt(dict)
+		for _, rounds := range []int{-1, 2} {
+			opts := lp.IntOptions{CutRounds: rounds, Eps: 1e-6}
+			res, err := lp.SolveIntOpts(dict, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(res.Obj-want) > 1e-6 {
+				t.Errorf("trial %d, rounds %d: got %g, want %g", trial, rounds, res.Obj, want)
+			}
+			if res.Gap > 1e-6 {
+				t.Errorf("trial %d, rounds %d: gap %g", trial, rounds, res.Gap)
+			}
+		}
+	}
+}
+
+func ExampleIntOptions() {
+	dict := new(lp.Dict)
+	dict.NonBasic = []int{0, 1}
+	dict.Basic = []int{2, 3, 4}
+	// max_{x, y >= 0} x + 2 y
+	dict.C = []float64{1, 2}
+	// subject to
+	// -x + y <= 1,     x -  y +  1 >= 0
+	// 3x + 2y <= 12, -3x - 2y + 12 >= 0
+	// 2x + 3y <= 12, -2x - 3y + 12 >= 0
+	dict.A = make([][]float64, 3)
+	dict.B = make([]float64, 3)
+	dict.A[0], dict.B[0] = []float64{1, -1}, 1
+	dict.A[1], dict.B[1] = []float64{-3, -2}, 12
+	dict.A[2], dict.B[2] = []float64{-2, -3}, 12
+
+	// Accept any solution within 20% of optimal
+	// using branch-and-bound without cuts.
+	opts := lp.IntOptions{
+		CutRounds:  -1,
+		RelGap:     0.2,
+		NodeLimit:  100,
+		Heuristics: []lp.Heuristic{lp.Diving{}},
+	}
+	res, err := lp.SolveIntOpts(dict, opts)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+	fmt.Printf("%.6g at %.6g\n", res.Obj, res.X[:2])
+	fmt.Printf("bound %.6g, gap %.3g, nodes %d\n", res.Bound, res.Gap, res.Nodes)
+	// Output:
+	// 6 at [2 2]
+	// bound 7.2, gap 0.2, nodes 2
+}
+
+func TestSolveIntOpts_workers(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for trial := 0; trial < 20; trial++ {
+		dict := randIntDict(r, 3, 4)
+		want := bruteForceInt(dict)
+		var nodes int
+		for _, det := range []bool{false, true, true} {
+			opts := lp.IntOptions{CutRounds: -1, Eps: 1e-6, Workers: 4, Deterministic: det}
+			res, err := lp.SolveIntOpts(dict, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(res.Obj-want) > 1e-6 {
+				t.Errorf("trial %d, deterministic %v: got %g, want %g", trial, det, res.Obj, want)
+			}
+			if det {
+				// Deterministic mode must explore the same nodes every time.
+				if nodes != 0 && res.Nodes != nodes {
+					t.Errorf("trial %d: nodes %d then %d", trial, nodes, res.Nodes)
+				}
+				nodes = res.Nodes
+			}
+		}
+	}
+}
+
+func TestSolveIntOpts_eps(t *testing.T) {
+	// max x + 1e-7 y subject to 2 x <= 5 and y <= 1.
+	// The objective coefficient of y is within Eps of zero
+	// but not within DefaultEps.
+	dict := lp.NewDict(2, 2)
+	dict.NonBasic = []int{0, 1}
+	dict.Basic = []int{2, 3}
+	dict.C = []float64{1, 1e-7}
+	dict.A = [][]float64{{-2, 0}, {0, -1}}
+	dict.B = []float64{5, 1}
+	for _, cutRounds := range []int{0, -1} {
+		res, err := lp.SolveIntOpts(dict, lp.IntOptions{Eps: 1e-6, CutRounds: cutRounds})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(res.Obj-2) > 1e-6 {
+			t.Errorf("cut rounds %d: got objective %g, want 2", cutRounds, res.Obj)
+		}
+	}
+}
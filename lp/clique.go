@@ -0,0 +1,128 @@
+package lp
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Clique separates clique inequalities
+//	sum_{k in Q} y[k] <= 1
+// where Q is a set of binary literals of which at most one can be one.
+// A literal y is either a binary variable x or its complement 1 - x.
+//
+// Conflicts between pairs of literals are found in the knapsack rows
+// of the original dictionary: two literals conflict if their weights
+// together exceed the capacity of a row.
+type Clique struct {
+	binary []int
+	// Literal 2*k is binary[k] and literal 2*k+1 is its complement.
+	adj [][]bool
+}
+
+// NewClique builds the conflict graph of the original dictionary.
+// The labels of the binary variables must be given.
+func NewClique(orig *Dict, binary []int) *Clique {
+	eps := DefaultEps
+	p := 2 * len(binary)
+	adj := make([][]bool, p)
+	for u := range adj {
+		adj[u] = make([]bool, p)
+	}
+	for k := range binary {
+		// A variable and its complement can not both be one.
+		adj[2*k][2*k+1], adj[2*k+1][2*k] = true, true
+	}
+
+	for _, row := range knapsacks(orig, binary, eps) {
+		lits := make([]int, len(row.Labels))
+		for s, lbl := range row.Labels {
+			k, _ := find(lbl, binary)
+			lits[s] = 2 * k
+			if row.Compl[s] {
+				lits[s]++
+			}
+		}
+		for s := range lits {
+			for t := s + 1; t < len(lits); t++ {
+				if row.Weights[s]+row.Weights[t] > row.Cap+eps {
+					adj[lits[s]][lits[t]], adj[lits[t]][lits[s]] = true, true
+				}
+			}
+		}
+	}
+	return &Clique{binary, adj}
+}
+
+func (sep *Clique) Separate(dict *Dict, eps float64) []Cut {
+	x := dict.values()
+	p := len(sep.adj)
+	v := make([]float64, p)
+	for k, lbl := range sep.binary {
+		v[2*k], v[2*k+1] = x[lbl], 1-x[lbl]
+	}
+
+	// Candidates to extend a clique in order of decreasing value.
+	order := make([]int, 0, p)
+	for u := 0; u < p; u++ {
+		if v[u] > eps {
+			order = append(order, u)
+		}
+	}
+	sort.SliceStable(order, func(s, t int) bool { return v[order[s]] > v[order[t]] })
+
+	var cuts []Cut
+	seen := make(map[string]bool)
+	for _, seed := range order {
+		if v[seed] >= 1-eps {
+			// Seed literal is integer.
+			continue
+		}
+		// Greedily grow clique from seed.
+		clique := []int{seed}
+		total := v[seed]
+		for _, u := range order {
+			if u == seed {
+				continue
+			}
+			all := true
+			for _, w := range clique {
+				if !sep.adj[u][w] {
+					all = false
+					break
+				}
+			}
+			if all {
+				clique = append(clique, u)
+				total += v[u]
+			}
+		}
+		if total <= 1+eps {
+			continue
+		}
+
+		sort.Ints(clique)
+		key := fmt.Sprint(clique)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		cuts = append(cuts, sep.cut(clique))
+	}
+	return cuts
+}
+
+// Returns the cut 1 - sum_{u in Q} y[u] >= 0 in terms of the original variables.
+func (sep *Clique) cut(clique []int) Cut {
+	cut := Cut{Const: 1}
+	for _, u := range clique {
+		cut.Labels = append(cut.Labels, sep.binary[u/2])
+		if u%2 == 1 {
+			// -(1 - x) = -1 + x
+			cut.Const--
+			cut.Coeffs = append(cut.Coeffs, 1)
+		} else {
+			cut.Coeffs = append(cut.Coeffs, -1)
+		}
+	}
+	return cut
+}
@@ -0,0 +1,170 @@
+package lp
+
+import "fmt"
+
+// Column is a variable to be added to a model
+// with its coefficients in the constraints.
+type Column struct {
+	Name   string
+	Obj    float64
+	Lower  float64
+	Upper  float64
+	Cons   []int
+	Coeffs []float64
+}
+
+// Pricer finds columns which improve the solution of the master problem.
+type Pricer interface {
+	// Price returns columns whose reduced cost
+	//	Obj - sum_k duals[Cons[k]] Coeffs[k]
+	// is positive (negative if the problem is a minimization),
+	// or no columns if there are none.
+	// The duals are those of ModelSoln.
+	Price(duals []float64) ([]Column, error)
+}
+
+// ColGenOptions controls column generation.
+type ColGenOptions struct {
+	// Options for solving the linear relaxation of the master problem.
+	LP Options
+	// Maximum number of times the master problem is solved.
+	// If zero, there is no limit.
+	MaxIter int
+	// Whether to solve the final master problem with integer variables.
+	// All variables of its dictionary must take integer values (see SolveInt),
+	// which requires integer coefficients and bounds.
+	Int bool
+	// Options for the integer solve.
+	IntOpts IntOptions
+}
+
+// ColGenResult is the outcome of column generation.
+type ColGenResult struct {
+	// Master problem with all generated columns.
+	Master *Model
+	// Solution of the linear relaxation of the final master problem.
+	Soln *ModelSoln
+	// Whether the pricer found no more improving columns,
+	// in which case Soln is optimal over all columns.
+	Optimal bool
+	// Number of times the master problem was solved.
+	Iter int
+	// Integer solution of the final master problem if Int was set.
+	// Nil if no integer solution was found.
+	IntX   []float64
+	IntObj float64
+}
+
+// ColumnGeneration solves a linear program with many variables
+// by solving a restricted master problem
+// and asking the pricer for columns which improve its solution.
+// The columns are added to (a copy of) the master problem
+// and it is re-solved starting from the previous basis.
+// This is repeated until the pricer returns no columns.
+// Optionally, the final master problem is then solved with integer variables,
+// which gives a feasible but not necessarily optimal integer solution.
+func ColumnGeneration(master *Model, pricer Pricer, opts ColGenOptions) (*ColGenResult, error) {
+	m := &Model{
+		Name:  master.Name,
+		Vars:  append([]Var(nil), master.Vars...),
+		Cons:  make([]Con, len(master.Cons)),
+		Const: master.Const,
+		Min:   master.Min,
+	}
+	for i, con := range master.Cons {
+		con.Vars = append([]int(nil), con.Vars...)
+		con.Coeffs = append([]float64(nil), con.Coeffs...)
+		m.Cons[i] = con
+	}
+
+	res := &ColGenResult{Master: m}
+	var (
+		basis *Basis
+		prev  *modelLayout
+	)
+	for opts.MaxIter == 0 || res.Iter < opts.MaxIter {
+		l := m.layout()
+		lpOpts := opts.LP
+		if basis != nil {
+			lpOpts.Basis = prev.mapBasis(l, basis)
+		}
+		final, err := SolveOpts(m.Dict(), lpOpts)
+		if err != nil {
+			return nil, fmt.Errorf("iteration %d: %v", res.Iter, err)
+		}
+		res.Iter++
+		res.Soln = m.Soln(final)
+		basis, prev = final.Basis(), l
+
+		cols, err := pricer.Price(res.Soln.Duals)
+		if err != nil {
+			return nil, err
+		}
+		if len(cols) == 0 {
+			res.Optimal = true
+			break
+		}
+		for k, col := range cols {
+			if len(col.Cons) != len(col.Coeffs) {
+				return nil, fmt.Errorf("column %d: %d constraints and %d coefficients", k, len(col.Cons), len(col.Coeffs))
+			}
+			for _, i := range col.Cons {
+				if i < 0 || i >= len(m.Cons) {
+					return nil, fmt.Errorf("column %d: no constraint %d", k, i)
+				}
+			}
+		}
+		for _, col := range cols {
+			j := m.AddVar(col.Name, col.Obj, col.Lower, col.Upper)
+			for k, i := range col.Cons {
+				m.Cons[i].Vars = append(m.Cons[i].Vars, j)
+				m.Cons[i].Coeffs = append(m.Cons[i].Coeffs, col.Coeffs[k])
+			}
+		}
+	}
+
+	if opts.Int {
+		r, err := SolveIntOpts(m.Dict(), opts.IntOpts)
+		if err != nil {
+			return nil, err
+		}
+		if r.X != nil {
+			res.IntX = m.layout().x(r.X)
+			res.IntObj = m.Obj(res.IntX)
+		}
+	}
+	return res, nil
+}
+
+// Maps the labels of a basis of the dictionary of a model
+// to the dictionary of the model with additional variables.
+// The slacks of the bounds of the new variables are basic.
+func (l *modelLayout) mapBasis(dst *modelLayout, b *Basis) *Basis {
+	labels := make(map[int]int)
+	for j := range l.cols {
+		for t, col := range l.cols[j] {
+			labels[col] = dst.cols[j][t]
+		}
+		labels[l.bound[j]] = dst.bound[j]
+	}
+	for i := range l.upper {
+		labels[l.upper[i]] = dst.upper[i]
+		labels[l.lower[i]] = dst.lower[i]
+	}
+	delete(labels, -1)
+
+	out := new(Basis)
+	for _, lbl := range b.Basic {
+		out.Basic = append(out.Basic, labels[lbl])
+	}
+	for _, lbl := range b.NonBasic {
+		out.NonBasic = append(out.NonBasic, labels[lbl])
+	}
+	for j := len(l.cols); j < len(dst.cols); j++ {
+		out.NonBasic = append(out.NonBasic, dst.cols[j]...)
+		if dst.bound[j] >= 0 {
+			out.Basic = append(out.Basic, dst.bound[j])
+		}
+	}
+	return out
+}
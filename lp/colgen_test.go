@@ -0,0 +1,160 @@
+package lp_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/jvlmdr/golp/lp"
+)
+
+// Finds cutting patterns for rolls of a given width.
+type cuttingStock struct {
+	width  int
+	widths []int
+}
+
+// Returns the pattern of maximum value which fits in a roll
+// by dynamic programming.
+func (p *cuttingStock) best(vals []float64) (float64, []int) {
+	value := make([]float64, p.width+1)
+	last := make([]int, p.width+1)
+	for w := 1; w <= p.width; w++ {
+		value[w], last[w] = value[w-1], -1
+		for i, wi := range p.widths {
+			if wi <= w && value[w-wi]+vals[i] > value[w] {
+				value[w], last[w] = value[w-wi]+vals[i], i
+			}
+		}
+	}
+	count := make([]int, len(p.widths))
+	for w := p.width; w > 0; {
+		if last[w] < 0 {
+			w--
+			continue
+		}
+		count[last[w]]++
+		w -= p.widths[last[w]]
+	}
+	return value[p.width], count
+}
+
+func (p *cuttingStock) Price(duals []float64) ([]lp.Column, error) {
+	value, count := p.best(duals)
+	if value <= 1+1e-9 {
+		return nil, nil
+	}
+	col := lp.Column{Obj: 1, Upper: math.Inf(1)}
+	for i, c := range count {
+		if c > 0 {
+			col.Cons = append(col.Cons, i)
+			col.Coeffs = append(col.Coeffs, float64(c))
+		}
+	}
+	return []lp.Column{col}, nil
+}
+
+// Returns the master problem of cutting stock
+// with one pattern for each width.
+func (p *cuttingStock) master(demand []float64) *lp.Model {
+	m := lp.NewModel()
+	m.Min = true
+	for i, w := range p.widths {
+		j := m.AddVar("", 1, 0, math.Inf(1))
+		m.AddCon("", demand[i], math.Inf(1), []int{j}, []float64{float64(p.width / w)})
+	}
+	return m
+}
+
+func ExampleColumnGeneration() {
+	// Cut rolls of width 10 into 9 pieces of width 3,
+	// 5 pieces of width 4 and 3 pieces of width 5.
+	p := &cuttingStock{width: 10, widths: []int{3, 4, 5}}
+	res, err := lp.ColumnGeneration(p.master([]float64{9, 5, 3}), p, lp.ColGenOptions{Int: true})
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+	fmt.Printf("%d columns, relaxation %.6g, integer %.6g\n", len(res.Master.Vars), res.Soln.Obj, res.IntObj)
+	// Output:
+	// 4 columns, relaxation 6.25, integer 7
+}
+
+func TestColumnGeneration(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 50; trial++ {
+		p := &cuttingStock{width: 10 + r.Intn(20)}
+		demand := make([]float64, 1+r.Intn(4))
+		for i := range demand {
+			p.widths = append(p.widths, 2+r.Intn(p.width-2))
+			demand[i] = float64(1 + r.Intn(20))
+		}
+		res, err := lp.ColumnGeneration(p.master(demand), p, lp.ColGenOptions{})
+		if err != nil {
+			t.Fatalf("trial %d: %v", trial, err)
+		}
+		if !res.Optimal {
+			t.Fatalf("trial %d: not optimal", trial)
+		}
+
+		// Compare to the master problem with every pattern.
+		all := lp.NewModel()
+		all.Min = true
+		for i := range demand {
+			all.AddCon("", demand[i], math.Inf(1), nil, nil)
+		}
+		var enum func(i, w int, count []int)
+		enum = func(i, w int, count []int) {
+			if i == len(p.widths) {
+				j := all.AddVar("", 1, 0, math.Inf(1))
+				for k, c := range count {
+					if c > 0 {
+						all.Cons[k].Vars = append(all.Cons[k].Vars, j)
+						all.Cons[k].Coeffs = append(all.Cons[k].Coeffs, float64(c))
+					}
+				}
+				return
+			}
+			for c := 0; c*p.widths[i] <= w; c++ {
+				count[i] = c
+				enum(i+1, w-c*p.widths[i], count)
+			}
+		}
+		enum(0, p.width, make([]int, len(p.widths)))
+		want, err := lp.SolveModel(all)
+		if err != nil {
+			t.Fatalf("trial %d: %v", trial, err)
+		}
+		if math.Abs(res.Soln.Obj-want.Obj) > 1e-6 {
+			t.Errorf("trial %d: got %g, want %g", trial, res.Soln.Obj, want.Obj)
+		}
+	}
+}
+
+// Returns the same columns every time.
+type fixedPricer []lp.Column
+
+func (p fixedPricer) Price(duals []float64) ([]lp.Column, error) {
+	return p, nil
+}
+
+func TestColumnGeneration_badColumn(t *testing.T) {
+	tests := []struct {
+		Col  lp.Column
+		Want string
+	}{
+		{lp.Column{Obj: 1, Upper: 1, Cons: []int{1}, Coeffs: []float64{1}}, "column 0: no constraint 1"},
+		{lp.Column{Obj: 1, Upper: 1, Cons: []int{-1}, Coeffs: []float64{1}}, "column 0: no constraint -1"},
+		{lp.Column{Obj: 1, Upper: 1, Cons: []int{0}, Coeffs: []float64{1, 2}}, "column 0: 1 constraints and 2 coefficients"},
+	}
+	for _, test := range tests {
+		m := lp.NewModel()
+		x := m.AddVar("x", 1, 0, 1)
+		m.AddCon("", math.Inf(-1), 1, []int{x}, []float64{1})
+		_, err := lp.ColumnGeneration(m, fixedPricer{test.Col}, lp.ColGenOptions{})
+		if err == nil || err.Error() != test.Want {
+			t.Errorf("%+v: got error %v, want %q", test.Col, err, test.Want)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package lp
+
+import "sort"
+
+// A knapsack is a row of the original problem which only limits binary variables,
+//	sum_k Weights[k] y[k] <= Cap,
+// where y[k] is x[Labels[k]] or its complement 1 - x[Labels[k]].
+// All weights are positive.
+type knapsack struct {
+	Labels  []int
+	Compl   []bool
+	Weights []float64
+	Cap     float64
+}
+
+// Finds the rows of the dictionary which can be written as knapsack constraints.
+// Non-binary variables with a non-negative weight are dropped from the row
+// and binary variables with a negative weight are complemented.
+func knapsacks(orig *Dict, binary []int, eps float64) []knapsack {
+	var rows []knapsack
+	for i := range orig.Basic {
+		// Basic variable is B[i] + sum_j A[i][j] x[j] >= 0,
+		// therefore the row is sum_j -A[i][j] x[j] <= B[i].
+		row := knapsack{Cap: orig.B[i]}
+		ok := true
+		for j, lbl := range orig.NonBasic {
+			w := -orig.A[i][j]
+			if w >= -eps && w <= eps {
+				continue
+			}
+			if _, bin := find(lbl, binary); !bin {
+				if w < 0 {
+					// Variable is not bounded above.
+					ok = false
+					break
+				}
+				// Dropping the variable relaxes the constraint.
+				continue
+			}
+			compl := false
+			if w < 0 {
+				// w x = w - w (1 - x)
+				row.Cap -= w
+				w, compl = -w, true
+			}
+			row.Labels = append(row.Labels, lbl)
+			row.Compl = append(row.Compl, compl)
+			row.Weights = append(row.Weights, w)
+		}
+		if !ok || len(row.Labels) < 2 || row.Cap < -eps {
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// Returns the values of y given the solution x.
+func (row knapsack) values(x []float64) []float64 {
+	v := make([]float64, len(row.Labels))
+	for k, lbl := range row.Labels {
+		v[k] = x[lbl]
+		if row.Compl[k] {
+			v[k] = 1 - v[k]
+		}
+	}
+	return v
+}
+
+// Returns the cut
+//	sum_k alpha[k] y[k] <= rhs
+// in terms of the original variables.
+func (row knapsack) cut(alpha []float64, rhs float64) Cut {
+	cut := Cut{Const: rhs}
+	for k, lbl := range row.Labels {
+		if alpha[k] == 0 {
+			continue
+		}
+		if row.Compl[k] {
+			// -alpha (1 - x) = -alpha + alpha x
+			cut.Const -= alpha[k]
+			cut.Labels = append(cut.Labels, lbl)
+			cut.Coeffs = append(cut.Coeffs, alpha[k])
+		} else {
+			cut.Labels = append(cut.Labels, lbl)
+			cut.Coeffs = append(cut.Coeffs, -alpha[k])
+		}
+	}
+	return cut
+}
+
+// KnapsackCover separates lifted cover inequalities
+// from the knapsack rows of the original dictionary.
+//
+// A cover C is a set of binary variables whose weights exceed the capacity of a row,
+// therefore at most |C| - 1 of them can be one.
+// The coefficients of the remaining variables are then sequentially lifted.
+type KnapsackCover struct {
+	rows []knapsack
+}
+
+// NewKnapsackCover finds the knapsack rows of the original dictionary.
+// The labels of the binary variables must be given.
+// The upper bounds of the binary variables are assumed to be constraints
+// in the dictionary or otherwise implied.
+func NewKnapsackCover(orig *Dict, binary []int) *KnapsackCover {
+	return &KnapsackCover{knapsacks(orig, binary, DefaultEps)}
+}
+
+func (sep *KnapsackCover) Separate(dict *Dict, eps float64) []Cut {
+	x := dict.values()
+	var cuts []Cut
+	for _, row := range sep.rows {
+		if cut, ok := separateCover(row, row.values(x), eps); ok {
+			cuts = append(cuts, cut)
+		}
+	}
+	return cuts
+}
+
+func separateCover(row knapsack, v []float64, eps float64) (Cut, bool) {
+	// Greedily find a cover which minimizes sum_{k in C} (1 - v[k]).
+	order := make([]int, len(v))
+	for k := range order {
+		order[k] = k
+	}
+	sort.SliceStable(order, func(s, t int) bool {
+		p, q := order[s], order[t]
+		return (1-v[p])/row.Weights[p] < (1-v[q])/row.Weights[q]
+	})
+	var (
+		cover []int
+		total float64
+	)
+	for _, k := range order {
+		if total > row.Cap+eps {
+			break
+		}
+		cover = append(cover, k)
+		total += row.Weights[k]
+	}
+	if total <= row.Cap+eps {
+		// Not possible to exceed capacity.
+		return Cut{}, false
+	}
+
+	// Make the cover minimal by removing the elements with the lowest values.
+	sort.SliceStable(cover, func(s, t int) bool { return v[cover[s]] < v[cover[t]] })
+	for s := 0; s < len(cover); {
+		k := cover[s]
+		if total-row.Weights[k] > row.Cap+eps {
+			total -= row.Weights[k]
+			cover = append(cover[:s], cover[s+1:]...)
+			continue
+		}
+		s++
+	}
+
+	alpha := make([]float64, len(v))
+	in := make([]bool, len(v))
+	for _, k := range cover {
+		alpha[k] = 1
+		in[k] = true
+	}
+	rhs := float64(len(cover) - 1)
+
+	// Lift remaining variables in order of decreasing value.
+	var rest []int
+	for k := range v {
+		if !in[k] {
+			rest = append(rest, k)
+		}
+	}
+	sort.SliceStable(rest, func(s, t int) bool { return v[rest[s]] > v[rest[t]] })
+	var (
+		vals []float64
+		wts  []float64
+	)
+	for _, k := range cover {
+		vals = append(vals, alpha[k])
+		wts = append(wts, row.Weights[k])
+	}
+	for _, k := range rest {
+		// Largest value of inequality if y[k] = 1.
+		r := row.Cap - row.Weights[k]
+		z := 0.0
+		if r >= -eps {
+			z = maxKnapsack(vals, wts, r, eps)
+		}
+		alpha[k] = rhs - z
+		if alpha[k] < eps {
+			alpha[k] = 0
+			continue
+		}
+		vals = append(vals, alpha[k])
+		wts = append(wts, row.Weights[k])
+	}
+
+	// Check whether inequality is violated.
+	var lhs float64
+	for k := range v {
+		lhs += alpha[k] * v[k]
+	}
+	if lhs <= rhs+eps {
+		return Cut{}, false
+	}
+	return row.cut(alpha, rhs), true
+}
+
+// Returns the maximum of sum_k val[k] y[k] subject to sum_k wt[k] y[k] <= cap
+// over binary y using depth-first branch-and-bound.
+// All values and weights must be positive.
+func maxKnapsack(val, wt []float64, cap, eps float64) float64 {
+	order := make([]int, len(val))
+	for k := range order {
+		order[k] = k
+	}
+	sort.SliceStable(order, func(s, t int) bool {
+		p, q := order[s], order[t]
+		return val[p]/wt[p] > val[q]/wt[q]
+	})
+
+	var best float64
+	var search func(s int, v, r float64)
+	search = func(s int, v, r float64) {
+		if v > best {
+			best = v
+		}
+		// Bound from the fractional relaxation.
+		bound, rem := v, r
+		for _, k := range order[s:] {
+			if wt[k] <= rem+eps {
+				bound += val[k]
+				rem -= wt[k]
+				continue
+			}
+			bound += val[k] * rem / wt[k]
+			break
+		}
+		if bound <= best+eps {
+			return
+		}
+		k := order[s]
+		if wt[k] <= r+eps {
+			search(s+1, v+val[k], r-wt[k])
+		}
+		search(s+1, v, r)
+	}
+	if len(order) > 0 {
+		search(0, 0, cap)
+	}
+	return best
+}
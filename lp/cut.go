@@ -0,0 +1,87 @@
+package lp
+
+// Cut is the inequality
+//	Const + sum_k Coeffs[k] x[Labels[k]] >= 0
+// in terms of the labels of any variables in the problem.
+// This matches the convention of the rows of a dictionary.
+type Cut struct {
+	Labels []int
+	Coeffs []float64
+	Const  float64
+}
+
+// Separator finds inequalities which are satisfied by all integer solutions
+// but violated by the solution associated with the dictionary.
+//
+// Like heuristics, separators are called by the integer solver
+// with dictionaries labelled 0, ..., p-1 in the order of the original labels.
+// A separator for a dictionary with other labels should be constructed
+// from the dictionary with these labels.
+type Separator interface {
+	Separate(dict *Dict, eps float64) []Cut
+}
+
+// AddCuts returns a new dictionary with the cuts appended as constraints.
+// Each cut is re-expressed in terms of the current non-basic variables
+// and a new slack variable is added to the basic set.
+func AddCuts(orig *Dict, cuts []Cut) *Dict {
+	A := make([][]float64, len(cuts))
+	B := make([]float64, len(cuts))
+	for i, cut := range cuts {
+		A[i], B[i] = orig.express(cut.Labels, cut.Coeffs, cut.Const)
+	}
+	return appendRows(orig, A, B)
+}
+
+// Returns the affine function
+//	d + sum_k coeffs[k] x[labels[k]]
+// in terms of the non-basic variables of the dictionary.
+func (dict *Dict) express(labels []int, coeffs []float64, d float64) (a []float64, b float64) {
+	a = make([]float64, len(dict.NonBasic))
+	b = d
+	for k, lbl := range labels {
+		c := coeffs[k]
+		if j, found := find(lbl, dict.NonBasic); found {
+			// Simply transfer coefficient.
+			a[j] += c
+			continue
+		}
+		i, found := find(lbl, dict.Basic)
+		if !found {
+			panic("variable not in dictionary")
+		}
+		// Transfer coefficients for basic variable.
+		b += c * dict.B[i]
+		for j := range a {
+			a[j] += c * dict.A[i][j]
+		}
+	}
+	return a, b
+}
+
+// Returns a copy of the dictionary with the given rows appended.
+// Each new row is assigned a new slack variable in the basic set
+// with a label greater than all existing labels.
+func appendRows(orig *Dict, A [][]float64, B []float64) *Dict {
+	m, n := len(orig.Basic), len(orig.NonBasic)
+	p := numVars(orig)
+
+	// Copy dictionary.
+	dict := NewDict(m+len(A), n)
+	copy(dict.Basic, orig.Basic)
+	copy(dict.NonBasic, orig.NonBasic)
+	for i := range orig.A {
+		copy(dict.A[i], orig.A[i])
+	}
+	copy(dict.B, orig.B)
+	copy(dict.C, orig.C)
+	dict.D = orig.D
+
+	// Add new rows and slack variables.
+	for i := range A {
+		dict.Basic[m+i] = p + i
+		dict.A[m+i] = A[i]
+		dict.B[m+i] = B[i]
+	}
+	return dict
+}
@@ -0,0 +1,201 @@
+package lp_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/jvlmdr/golp/lp"
+)
+
+func ExampleKnapsackCover() {
+	dict := new(lp.Dict)
+	dict.NonBasic = []int{0, 1, 2}
+	dict.Basic = []int{3, 4, 5, 6, 7, 8}
+	// max_{x in {0, 1}^3} 5 x + 4 y + 3 z
+	dict.C = []float64{5, 4, 3}
+	// subject to
+	// 2x + 3y +  z <= 5
+	// 4x +  y + 2z <= 11
+	// 3x + 4y + 2z <= 8
+	// x, y, z <= 1
+	dict.A = [][]float64{
+		{-2, -3, -1},
+		{-4, -1, -2},
+		{-3, -4, -2},
+		{-1, 0, 0},
+		{0, -1, 0},
+		{0, 0, -1},
+	}
+	dict.B = []float64{5, 11, 8, 1, 1, 1}
+
+	binary := []int{0, 1, 2}
+	opts := lp.IntOptions{
+		Separators: []lp.Separator{
+			lp.NewKnapsackCover(dict, binary),
+			lp.NewClique(dict, binary),
+		},
+	}
+	res, err := lp.SolveIntOpts(dict, opts)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+	x := make([]int, 3)
+	for i := range x {
+		x[i] = int(math.Round(res.X[i]))
+	}
+	fmt.Printf("%.6g at %d\n", res.Obj, x)
+	// Output:
+	// 9 at [1 1 0]
+}
+
+func TestClique(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var total int
+	for trial := 0; trial < 200; trial++ {
+		// Random rows with positive and negative weights over n binary variables,
+		// whose conflicts define the graph.
+		n, m := 2+r.Intn(4), 1+r.Intn(3)
+		orig := lp.NewDict(m, n)
+		binary := make([]int, n)
+		for j := range orig.NonBasic {
+			orig.NonBasic[j] = j
+			binary[j] = j
+			orig.C[j] = 1
+		}
+		for i := range orig.Basic {
+			orig.Basic[i] = n + i
+			for j := range orig.A[i] {
+				orig.A[i][j] = -float64(r.Intn(7) - 2)
+			}
+			orig.B[i] = float64(r.Intn(6))
+		}
+		sep := lp.NewClique(orig, binary)
+
+		// Integer points which satisfy the rows.
+		var points [][]float64
+		for bits := 0; bits < 1<<uint(n); bits++ {
+			x := make([]float64, n)
+			for j := range x {
+				x[j] = float64(bits >> uint(j) & 1)
+			}
+			feas := true
+			for i := range orig.Basic {
+				v := orig.B[i]
+				for j := range x {
+					v += orig.A[i][j] * x[j]
+				}
+				feas = feas && v >= 0
+			}
+			if feas {
+				points = append(points, x)
+			}
+		}
+
+		// Separate random fractional points.
+		for k := 0; k < 10; k++ {
+			relax := lp.NewDict(n, m)
+			for j := range relax.Basic {
+				relax.Basic[j] = j
+				relax.B[j] = r.Float64()
+			}
+			for i := range relax.NonBasic {
+				relax.NonBasic[i] = n + i
+			}
+			for _, cut := range sep.Separate(relax, lp.DefaultEps) {
+				total++
+				for _, x := range points {
+					v := cut.Const
+					for s, lbl := range cut.Labels {
+						v += cut.Coeffs[s] * x[lbl]
+					}
+					if v < -1e-9 {
+						t.Fatalf("trial %d: cut %+v is violated by integer point %v", trial, cut, x)
+					}
+				}
+			}
+		}
+	}
+	if total == 0 {
+		t.Errorf("no cuts found")
+	}
+}
+
+func TestKnapsackCover(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var total int
+	for trial := 0; trial < 300; trial++ {
+		// Random knapsack rows with positive and negative weights
+		// over n binary variables, followed by their upper bounds.
+		n, m := 2+r.Intn(5), 1+r.Intn(2)
+		orig := lp.NewDict(m+n, n)
+		binary := make([]int, n)
+		for j := range orig.NonBasic {
+			orig.NonBasic[j] = j
+			binary[j] = j
+			orig.C[j] = float64(1 + r.Intn(5))
+		}
+		for i := 0; i < m; i++ {
+			orig.Basic[i] = n + i
+			for j := range orig.A[i] {
+				orig.A[i][j] = -float64(r.Intn(9) - 2)
+			}
+			orig.B[i] = float64(1 + r.Intn(8))
+		}
+		for j := 0; j < n; j++ {
+			orig.Basic[m+j] = n + m + j
+			orig.A[m+j][j] = -1
+			orig.B[m+j] = 1
+		}
+		sep := lp.NewKnapsackCover(orig, binary)
+
+		// Integer points which satisfy the rows.
+		var points [][]float64
+		for bits := 0; bits < 1<<uint(n); bits++ {
+			x := make([]float64, n)
+			for j := range x {
+				x[j] = float64(bits >> uint(j) & 1)
+			}
+			feas := true
+			for i := 0; i < m; i++ {
+				v := orig.B[i]
+				for j := range x {
+					v += orig.A[i][j] * x[j]
+				}
+				feas = feas && v >= 0
+			}
+			if feas {
+				points = append(points, x)
+			}
+		}
+
+		relax, err := lp.Solve(orig)
+		if err != nil {
+			t.Fatalf("trial %d: %v", trial, err)
+		}
+		x := relax.Soln()[:n]
+		for _, cut := range sep.Separate(relax, lp.DefaultEps) {
+			total++
+			value := func(x []float64) float64 {
+				v := cut.Const
+				for s, lbl := range cut.Labels {
+					v += cut.Coeffs[s] * x[lbl]
+				}
+				return v
+			}
+			if v := value(x); v >= -lp.DefaultEps {
+				t.Errorf("trial %d: cut %+v does not cut off relaxation %g: value %g", trial, cut, x, v)
+			}
+			for _, p := range points {
+				if v := value(p); v < -1e-9 {
+					t.Fatalf("trial %d: cut %+v is violated by integer point %v", trial, cut, p)
+				}
+			}
+		}
+	}
+	if total == 0 {
+		t.Errorf("no cuts found")
+	}
+}
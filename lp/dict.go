@@ -1,5 +1,11 @@
 package lp
 
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
 var DefaultEps = 1e-9
 
 // Dict is a "dictionary" describing a linear program.
@@ -21,6 +27,14 @@ var DefaultEps = 1e-9
 // A dictionary is feasible if all B[i] >= 0
 // since this implies that if all non-basic variables are zero,
 // then all non-basic variables are non-negative.
+//
+// Variables are identified by distinct integer labels,
+// which may be negative and need not be contiguous.
+// The solvers work internally with labels 0, ..., m+n-1
+// and return dictionaries in terms of the original labels.
+// Variables which are added to a dictionary (for example,
+// the auxiliary variable of the feasibility problem or the slack of a cut)
+// are given labels greater than all existing labels.
 type Dict struct {
 	Basic    []int
 	NonBasic []int
@@ -48,13 +62,33 @@ func NewDict(m, n int) *Dict {
 
 // Soln returns the solution associated with the dictionary.
 // This is the value of all variables when the non-basic variables are zero.
+// The values are in increasing order of label (see Labels).
+// If the labels are 0, ..., m+n-1, the solution is indexed by label.
 func (dict *Dict) Soln() []float64 {
-	p := len(dict.Basic) + len(dict.NonBasic)
-	x := make([]float64, p)
+	labels := dict.Labels()
+	x := make([]float64, len(labels))
 	// Non-basic are all zero (default value).
 	// Therefore basic = b.
-	for i, j := range dict.Basic {
-		x[j] = dict.B[i]
+	for i, lbl := range dict.Basic {
+		x[sort.SearchInts(labels, lbl)] = dict.B[i]
+	}
+	return x
+}
+
+// Labels returns the labels of all variables in increasing order.
+func (dict *Dict) Labels() []int {
+	labels := make([]int, 0, len(dict.Basic)+len(dict.NonBasic))
+	labels = append(append(labels, dict.Basic...), dict.NonBasic...)
+	sort.Ints(labels)
+	return labels
+}
+
+// Returns the solution indexed by label, of length numVars.
+// Used internally with dictionaries whose labels are dense (see dense).
+func (dict *Dict) values() []float64 {
+	x := make([]float64, numVars(dict))
+	for i, lbl := range dict.Basic {
+		x[lbl] = dict.B[i]
 	}
 	return x
 }
@@ -137,7 +171,7 @@ func (src *Dict) Pivot(enter, leave int) *Dict {
 
 // ToFeasDict creates a dictionary describing the feasibility problem.
 // Adds a variable to the basic set, then pivots it into the non-basic set.
-// Assumes that Basic and NonBasic are indices from 0 to len(NonBasic)+len(Basic)-1.
+// The new variable is labelled one more than the largest existing label.
 func ToFeasDict(infeas *Dict) *Dict {
 	m, n := len(infeas.Basic), len(infeas.NonBasic)
 	// Add a new non-basic variable.
@@ -146,7 +180,7 @@ func ToFeasDict(infeas *Dict) *Dict {
 	// Copy constraints.
 	copy(dict.Basic, infeas.Basic)
 	copy(dict.NonBasic, infeas.NonBasic)
-	dict.NonBasic[n] = m + n
+	dict.NonBasic[n] = numVars(infeas)
 	for i := 0; i < m; i++ {
 		copy(dict.A[i], infeas.A[i])
 	}
@@ -164,27 +198,45 @@ func ToFeasDict(infeas *Dict) *Dict {
 }
 
 // FromFeasDict returns to original problem.
-// Removes the variable with label len(NonBasic)+len(Basic)-1,
-// which must be in the non-basic set.
-func FromFeasDict(feas *Dict, orig *Dict) *Dict {
-	// Size of the original problem.
-	m, n := len(feas.Basic), len(feas.NonBasic)-1
+// Removes the variable with the largest label (the auxiliary variable),
+// which must be zero.
+//
+// If the auxiliary variable is basic, it is first moved to the non-basic set
+// by a degenerate pivot. If this is not possible because its row is
+// numerically zero, the row is redundant and is removed instead,
+// leaving a dictionary with one fewer basic variable.
+// Returns an error if the auxiliary variable is basic and not zero,
+// in which case the original problem is infeasible.
+func FromFeasDict(feas *Dict, orig *Dict) (*Dict, error) {
+	return FromFeasDictEps(feas, orig, DefaultEps)
+}
 
-	// Remove extra variable.
-	// Must be non-basic.
-	extra, found := find(m+n, feas.NonBasic)
-	if !found {
-		panic("extra variable not in non-basic set")
+func FromFeasDictEps(feas *Dict, orig *Dict, eps float64) (*Dict, error) {
+	aux := numVars(feas) - 1
+	if i, found := find(aux, feas.Basic); found {
+		if math.Abs(feas.B[i]) > eps {
+			return nil, fmt.Errorf("auxiliary variable %d is %g, not zero", aux, feas.B[i])
+		}
+		feas = driveOutAux(feas, i, eps)
+	}
+
+	// Remove extra variable if it is (now) non-basic.
+	extra, found := find(aux, feas.NonBasic)
+	m, n := len(feas.Basic), len(feas.NonBasic)
+	if found {
+		n--
+	} else {
+		extra = n
 	}
 
 	dict := NewDict(m, n)
 	// Copy constraints.
 	copy(dict.Basic, feas.Basic)
 	copy(dict.NonBasic[:extra], feas.NonBasic[:extra])
-	copy(dict.NonBasic[extra:], feas.NonBasic[extra+1:])
+	copy(dict.NonBasic[extra:], feas.NonBasic[min(extra+1, len(feas.NonBasic)):])
 	for i := 0; i < m; i++ {
 		copy(dict.A[i][:extra], feas.A[i][:extra])
-		copy(dict.A[i][extra:], feas.A[i][extra+1:])
+		copy(dict.A[i][extra:], feas.A[i][min(extra+1, len(feas.NonBasic)):])
 	}
 	copy(dict.B, feas.B)
 
@@ -211,5 +263,152 @@ func FromFeasDict(feas *Dict, orig *Dict) *Dict {
 			}
 		}
 	}
+	return dict, nil
+}
+
+// Returns the feasible dictionary of the original problem
+// given the final dictionary of a feasibility problem,
+// or infeas if the auxiliary variable is not zero.
+func fromFeasEps(feas, orig *Dict, eps float64) (dict *Dict, infeas bool) {
+	dict, err := FromFeasDictEps(feas, orig, eps)
+	if err != nil {
+		return nil, true
+	}
+	return dict, false
+}
+
+// Moves the auxiliary variable, which is basic in row i at zero level,
+// to the non-basic set by a degenerate pivot.
+// The entering variable is the one with the largest coefficient in the row.
+// If every coefficient is within eps of zero, the row is removed instead.
+func driveOutAux(feas *Dict, i int, eps float64) *Dict {
+	var (
+		arg int
+		max float64
+	)
+	for j, aij := range feas.A[i] {
+		if math.Abs(aij) > max {
+			arg, max = j, math.Abs(aij)
+		}
+	}
+	if max > eps {
+		// Pivot exactly at zero level so that other rows are unchanged.
+		dict := feas.Clone()
+		dict.B[i] = 0
+		return dict.Pivot(arg, i)
+	}
+
+	// The row is redundant.
+	m := len(feas.Basic)
+	dict := &Dict{
+		Basic:    make([]int, 0, m-1),
+		NonBasic: append([]int(nil), feas.NonBasic...),
+		A:        make([][]float64, 0, m-1),
+		B:        make([]float64, 0, m-1),
+		C:        append([]float64(nil), feas.C...),
+		D:        feas.D,
+	}
+	for k := range feas.Basic {
+		if k == i {
+			continue
+		}
+		dict.Basic = append(dict.Basic, feas.Basic[k])
+		dict.A = append(dict.A, append([]float64(nil), feas.A[k]...))
+		dict.B = append(dict.B, feas.B[k])
+	}
 	return dict
 }
+
+// Returns one more than the largest label in the dictionary, or zero.
+// Variables added to the dictionary are labelled from here.
+// For a dense dictionary, this is the number of variables.
+func numVars(dict *Dict) int {
+	var p int
+	for _, lbl := range dict.Basic {
+		p = max(p, lbl+1)
+	}
+	for _, lbl := range dict.NonBasic {
+		p = max(p, lbl+1)
+	}
+	return p
+}
+
+// Returns an equivalent dictionary whose labels are 0, ..., m+n-1
+// in the same order as the original labels,
+// and the original labels in increasing order.
+// The dictionary itself is returned if it is already dense.
+func (dict *Dict) dense() (*Dict, []int) {
+	labels := dict.Labels()
+	if isDense(labels) {
+		return dict, labels
+	}
+	dst := dict.Clone()
+	for i, lbl := range dict.Basic {
+		dst.Basic[i] = sort.SearchInts(labels, lbl)
+	}
+	for j, lbl := range dict.NonBasic {
+		dst.NonBasic[j] = sort.SearchInts(labels, lbl)
+	}
+	return dst, labels
+}
+
+// Returns a dictionary in terms of the original labels
+// given a dictionary obtained from dense (and subsequently pivoted).
+// Variables which were added since are labelled
+// from one more than the largest original label.
+func sparse(dict *Dict, labels []int) *Dict {
+	if dict == nil || isDense(labels) {
+		return dict
+	}
+	p := len(labels)
+	f := func(lbl int) int {
+		if lbl < p {
+			return labels[lbl]
+		}
+		return labels[p-1] + 1 + lbl - p
+	}
+	dst := dict.Clone()
+	for i, lbl := range dict.Basic {
+		dst.Basic[i] = f(lbl)
+	}
+	for j, lbl := range dict.NonBasic {
+		dst.NonBasic[j] = f(lbl)
+	}
+	return dst
+}
+
+// Returns the basis in terms of the labels of a dense dictionary.
+// Labels which are not in the dictionary are mapped to -1.
+func denseBasis(b *Basis, labels []int) *Basis {
+	f := func(src []int) []int {
+		dst := make([]int, len(src))
+		for k, lbl := range src {
+			dst[k] = -1
+			if i := sort.SearchInts(labels, lbl); i < len(labels) && labels[i] == lbl {
+				dst[k] = i
+			}
+		}
+		return dst
+	}
+	return &Basis{Basic: f(b.Basic), NonBasic: f(b.NonBasic)}
+}
+
+// Returns true if the sorted distinct labels are 0, ..., len(labels)-1.
+func isDense(labels []int) bool {
+	return len(labels) == 0 || labels[0] == 0 && labels[len(labels)-1] == len(labels)-1
+}
+
+// Clone returns a deep copy of the dictionary.
+func (src *Dict) Clone() *Dict {
+	m, n := len(src.Basic), len(src.NonBasic)
+	dst := NewDict(m, n)
+	copy(dst.Basic, src.Basic)
+	copy(dst.NonBasic, src.NonBasic)
+	for i := range src.A {
+		copy(dst.A[i], src.A[i])
+	}
+	copy(dst.B, src.B)
+	copy(dst.C, src.C)
+	dst.D = src.D
+	return dst
+}
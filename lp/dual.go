@@ -26,3 +26,15 @@ func (p *Dict) Dual() *Dict {
 
 	return d
 }
+
+// Re-solves a dictionary which is dual feasible (all C[j] <= eps)
+// by pivoting in the dual dictionary.
+// Returns infeas if the primal problem is infeasible.
+func pivotToFinalDualEps(dict *Dict, eps float64) (final *Dict, infeas bool) {
+	dual, unbnd := pivotToFinalEps(dict.Dual(), eps)
+	if unbnd {
+		// Dual is unbounded, therefore primal is infeasible.
+		return nil, true
+	}
+	return dual.Dual(), false
+}
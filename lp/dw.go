@@ -0,0 +1,329 @@
+package lp
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// DWOptions controls Dantzig-Wolfe decomposition.
+type DWOptions struct {
+	// Tolerance for reduced costs and feasibility.
+	// If zero, DefaultEps is used.
+	Eps float64
+	// Penalty of the artificial variables of the linking constraints.
+	// If zero, 1e6 is used.
+	BigM float64
+	// Maximum number of times the master problem is solved.
+	// If zero, there is no limit.
+	MaxIter int
+	// Number of goroutines which solve block subproblems concurrently.
+	// If zero, one is used.
+	Workers int
+}
+
+// DWResult is the outcome of Dantzig-Wolfe decomposition.
+type DWResult struct {
+	// Solution of the original model, recovered from the master problem.
+	// It satisfies the linking constraints
+	// but may not be optimal if Optimal is false.
+	X   []float64
+	Obj float64
+	// Whether no block has a column with improving reduced cost,
+	// in which case X is optimal.
+	Optimal bool
+	// Number of times the master problem was solved.
+	Iter int
+	// Number of columns (extreme points of blocks) generated.
+	Columns int
+}
+
+// A block of a decomposed model.
+type dwBlock struct {
+	// Constraints of the block in terms of its own variables.
+	model *Model
+	// Index in the original model of each variable of the block.
+	vars []int
+}
+
+// SolveDantzigWolfe solves a block-angular model
+// using Dantzig-Wolfe decomposition.
+// Each element of blocks gives the indices of the constraints of a block.
+// The remaining constraints are linking constraints.
+// The variables of each block are those in its constraints
+// and must not appear in the constraints of other blocks.
+// Variables which are not in any block form blocks of their own
+// and must have finite bounds.
+// Every block must be bounded.
+//
+// The master problem chooses a convex combination of the extreme points
+// of each block subject to the linking constraints.
+// Artificial variables with penalty BigM make the master problem feasible
+// before enough extreme points are known.
+// It is solved by ColumnGeneration
+// with the blocks as pricing problems, which are solved concurrently.
+// Returns an error if the model is infeasible,
+// or if MaxIter is reached before a solution which satisfies
+// the linking constraints is found.
+func SolveDantzigWolfe(m *Model, blocks [][]int, opts DWOptions) (*DWResult, error) {
+	eps := opts.Eps
+	if eps == 0 {
+		eps = DefaultEps
+	}
+	bigM := opts.BigM
+	if bigM == 0 {
+		bigM = 1e6
+	}
+	sense := 1.0
+	if m.Min {
+		sense = -1
+	}
+
+	bs, linking, err := dwBlocks(m, blocks)
+	if err != nil {
+		return nil, err
+	}
+
+	// Linking constraints are followed by the convexity constraint of each block.
+	master := &Model{Min: m.Min, Const: m.Const}
+	for _, i := range linking {
+		con := m.Cons[i]
+		master.AddCon(con.Name, con.Lower, con.Upper, nil, nil)
+	}
+	for range bs {
+		master.AddCon("", 1, 1, nil, nil)
+	}
+	inf := math.Inf(1)
+	for k := range linking {
+		for _, a := range []float64{1, -1} {
+			j := master.AddVar("", -sense*bigM, 0, inf)
+			master.Cons[k].Vars = append(master.Cons[k].Vars, j)
+			master.Cons[k].Coeffs = append(master.Cons[k].Coeffs, a)
+		}
+	}
+	numArt := len(master.Vars)
+
+	p := &dwPricer{
+		orig:    m,
+		blocks:  bs,
+		linking: linking,
+		sense:   sense,
+		eps:     eps,
+		workers: opts.Workers,
+	}
+	// Add an initial extreme point of each block.
+	zero := make([]float64, len(master.Cons))
+	cols, err := p.price(zero, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, col := range cols {
+		j := master.AddVar(col.Name, col.Obj, col.Lower, col.Upper)
+		for k, i := range col.Cons {
+			master.Cons[i].Vars = append(master.Cons[i].Vars, j)
+			master.Cons[i].Coeffs = append(master.Cons[i].Coeffs, col.Coeffs[k])
+		}
+	}
+
+	cg, err := ColumnGeneration(master, p, ColGenOptions{
+		LP:      Options{Eps: eps},
+		MaxIter: opts.MaxIter,
+	})
+	if err != nil {
+		return nil, err
+	}
+	lambda := cg.Soln.X
+	for j := 0; j < numArt; j++ {
+		if lambda[j] <= math.Sqrt(eps) {
+			continue
+		}
+		if !cg.Optimal {
+			return nil, fmt.Errorf("linking constraints not satisfied after %d iterations", cg.Iter)
+		}
+		return nil, errors.New("infeasible problem")
+	}
+
+	res := &DWResult{
+		X:       make([]float64, len(m.Vars)),
+		Optimal: cg.Optimal,
+		Iter:    cg.Iter,
+		Columns: len(p.points),
+	}
+	for k, pt := range p.points {
+		b := bs[pt.block]
+		for t, j := range b.vars {
+			res.X[j] += lambda[numArt+k] * pt.x[t]
+		}
+	}
+	res.Obj = m.Obj(res.X)
+	return res, nil
+}
+
+// Partitions the variables and constraints of a model into blocks
+// and returns the indices of the linking constraints.
+func dwBlocks(m *Model, blocks [][]int) ([]dwBlock, []int, error) {
+	owner := make([]int, len(m.Vars))
+	for j := range owner {
+		owner[j] = -1
+	}
+	inBlock := make([]bool, len(m.Cons))
+	bs := make([]dwBlock, len(blocks))
+	// Index of each variable within its block.
+	local := make([]int, len(m.Vars))
+	for b, cons := range blocks {
+		bs[b].model = NewModel()
+		for _, i := range cons {
+			if i < 0 || i >= len(m.Cons) {
+				return nil, nil, fmt.Errorf("block %d: no constraint %d", b, i)
+			}
+			if inBlock[i] {
+				return nil, nil, fmt.Errorf("block %d: constraint %d is in another block", b, i)
+			}
+			inBlock[i] = true
+			for _, j := range m.Cons[i].Vars {
+				if owner[j] == b {
+					continue
+				}
+				if owner[j] >= 0 {
+					return nil, nil, fmt.Errorf("variable %d is in blocks %d and %d", j, owner[j], b)
+				}
+				owner[j] = b
+				v := m.Vars[j]
+				local[j] = bs[b].model.AddVar(v.Name, 0, v.Lower, v.Upper)
+				bs[b].vars = append(bs[b].vars, j)
+			}
+		}
+	}
+	for j, v := range m.Vars {
+		if owner[j] < 0 {
+			if math.IsInf(v.Lower, 0) || math.IsInf(v.Upper, 0) {
+				return nil, nil, fmt.Errorf("variable %d is in no block and has an infinite bound", j)
+			}
+			b := dwBlock{model: NewModel(), vars: []int{j}}
+			b.model.AddVar(v.Name, 0, v.Lower, v.Upper)
+			local[j] = 0
+			bs = append(bs, b)
+		}
+	}
+	for b, cons := range blocks {
+		for _, i := range cons {
+			con := m.Cons[i]
+			vars := make([]int, len(con.Vars))
+			for k, j := range con.Vars {
+				vars[k] = local[j]
+			}
+			bs[b].model.AddCon(con.Name, con.Lower, con.Upper, vars, con.Coeffs)
+		}
+	}
+	var linking []int
+	for i := range m.Cons {
+		if !inBlock[i] {
+			linking = append(linking, i)
+		}
+	}
+	return bs, linking, nil
+}
+
+// Prices the blocks of a Dantzig-Wolfe master problem.
+type dwPricer struct {
+	orig    *Model
+	blocks  []dwBlock
+	linking []int
+	sense   float64
+	eps     float64
+	workers int
+	// Extreme point of each column after the artificial variables.
+	points []dwPoint
+}
+
+type dwPoint struct {
+	block int
+	x     []float64
+}
+
+func (p *dwPricer) Price(duals []float64) ([]Column, error) {
+	return p.price(duals, false)
+}
+
+// Solves the block subproblems for the given duals
+// and returns the columns with improving reduced cost,
+// or a column for every block if all is true.
+func (p *dwPricer) price(duals []float64, all bool) ([]Column, error) {
+	type result struct {
+		x   []float64
+		obj float64
+		err error
+	}
+	results := make([]result, len(p.blocks))
+	// Dual of the linking constraints for each variable of the model.
+	prices := make([]float64, len(p.orig.Vars))
+	for k, i := range p.linking {
+		con := p.orig.Cons[i]
+		for t, j := range con.Vars {
+			prices[j] += duals[k] * con.Coeffs[t]
+		}
+	}
+
+	workers := p.workers
+	if workers < 1 {
+		workers = 1
+	}
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := range next {
+				blk := p.blocks[b]
+				// Each worker modifies its own copy of the block.
+				sub := &Model{Min: p.orig.Min, Vars: append([]Var(nil), blk.model.Vars...), Cons: blk.model.Cons}
+				for t, j := range blk.vars {
+					sub.Vars[t].Obj = p.orig.Vars[j].Obj - prices[j]
+				}
+				s, err := SolveModel(sub)
+				if err != nil {
+					results[b].err = fmt.Errorf("block %d: %v", b, err)
+					continue
+				}
+				results[b].x, results[b].obj = s.X, s.Obj
+			}
+		}()
+	}
+	for b := range p.blocks {
+		next <- b
+	}
+	close(next)
+	wg.Wait()
+
+	var cols []Column
+	for b, r := range results {
+		if r.err != nil {
+			return nil, r.err
+		}
+		conv := len(p.linking) + b
+		if !all && p.sense*(r.obj-duals[conv]) <= p.eps {
+			continue
+		}
+		col := Column{Upper: math.Inf(1)}
+		for t, j := range p.blocks[b].vars {
+			col.Obj += p.orig.Vars[j].Obj * r.x[t]
+		}
+		x := make([]float64, len(p.orig.Vars))
+		for t, j := range p.blocks[b].vars {
+			x[j] = r.x[t]
+		}
+		for k, i := range p.linking {
+			if a := p.orig.Cons[i].Activity(x); a != 0 {
+				col.Cons = append(col.Cons, k)
+				col.Coeffs = append(col.Coeffs, a)
+			}
+		}
+		col.Cons = append(col.Cons, conv)
+		col.Coeffs = append(col.Coeffs, 1)
+		cols = append(cols, col)
+		p.points = append(p.points, dwPoint{b, r.x})
+	}
+	return cols, nil
+}
@@ -0,0 +1,132 @@
+package lp_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/jvlmdr/golp/lp"
+)
+
+func ExampleSolveDantzigWolfe() {
+	inf := math.Inf(1)
+	m := lp.NewModel()
+	// Two sites produce x1, y1 and x2, y2 with profits 3, 2 and 4, 1.
+	x1 := m.AddVar("x1", 3, 0, inf)
+	y1 := m.AddVar("y1", 2, 0, inf)
+	x2 := m.AddVar("x2", 4, 0, inf)
+	y2 := m.AddVar("y2", 1, 0, inf)
+	// Capacity of each site.
+	m.AddCon("site1", math.Inf(-1), 4, []int{x1, y1}, []float64{1, 1})
+	m.AddCon("site2", math.Inf(-1), 6, []int{x2, y2}, []float64{2, 1})
+	// Shared demand for x.
+	m.AddCon("demand", math.Inf(-1), 3, []int{x1, x2}, []float64{1, 1})
+
+	res, err := lp.SolveDantzigWolfe(m, [][]int{{0}, {1}}, lp.DWOptions{Workers: 2})
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+	fmt.Printf("%.6g at %.6g\n", res.Obj, res.X)
+	// Output:
+	// 20 at [0 4 3 0]
+}
+
+func TestSolveDantzigWolfe(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 100; trial++ {
+		m := lp.NewModel()
+		m.Min = r.Intn(2) == 0
+		var blocks [][]int
+		var x []float64
+		for b := r.Intn(4); b >= 0; b-- {
+			// Variables of the block are bounded.
+			var vars []int
+			for k := 1 + r.Intn(3); k > 0; k-- {
+				lo := float64(r.Intn(3) - 1)
+				vars = append(vars, m.AddVar("", float64(r.Intn(11)-5), lo, lo+float64(1+r.Intn(4))))
+				x = append(x, lo+float64(r.Intn(2)))
+			}
+			var cons []int
+			for k := r.Intn(3); k > 0; k-- {
+				con := lp.Con{Vars: vars, Coeffs: make([]float64, len(vars))}
+				for t := range con.Coeffs {
+					con.Coeffs[t] = float64(r.Intn(7) - 3)
+				}
+				act := con.Activity(x)
+				cons = append(cons, m.AddCon("", act-float64(r.Intn(3)), act+float64(r.Intn(3)), con.Vars, con.Coeffs))
+			}
+			blocks = append(blocks, cons)
+		}
+		// Linking constraints, which may make the model infeasible.
+		for k := 1 + r.Intn(3); k > 0; k-- {
+			var con lp.Con
+			for j := range m.Vars {
+				if r.Intn(2) == 0 {
+					con.Vars = append(con.Vars, j)
+					con.Coeffs = append(con.Coeffs, float64(r.Intn(7)-3))
+				}
+			}
+			act := con.Activity(x) + float64(r.Intn(3)-1)
+			if r.Intn(2) == 0 {
+				m.AddCon("", act, math.Inf(1), con.Vars, con.Coeffs)
+			} else {
+				m.AddCon("", math.Inf(-1), act, con.Vars, con.Coeffs)
+			}
+		}
+
+		want, wantErr := lp.SolveModel(m)
+		got, err := lp.SolveDantzigWolfe(m, blocks, lp.DWOptions{Workers: 1 + r.Intn(3)})
+		if (err == nil) != (wantErr == nil) {
+			t.Fatalf("trial %d: got error %v, want %v", trial, err, wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if !got.Optimal {
+			t.Fatalf("trial %d: not optimal", trial)
+		}
+		if math.Abs(got.Obj-want.Obj) > 1e-6 {
+			t.Errorf("trial %d: got %g, want %g", trial, got.Obj, want.Obj)
+		}
+		for i, con := range m.Cons {
+			if act := con.Activity(got.X); act < con.Lower-1e-6 || act > con.Upper+1e-6 {
+				t.Errorf("trial %d: constraint %d: %g not in [%g, %g]", trial, i, act, con.Lower, con.Upper)
+			}
+		}
+	}
+}
+
+func TestSolveDantzigWolfe_limits(t *testing.T) {
+	inf := math.Inf(1)
+	// min x + y subject to x + y >= 3 and 0 <= x, y <= 2.
+	// The initial extreme points x = y = 0 violate the linking constraint.
+	m := lp.NewModel()
+	m.Min = true
+	x := m.AddVar("x", 1, 0, inf)
+	y := m.AddVar("y", 1, 0, inf)
+	m.AddCon("x", math.Inf(-1), 2, []int{x}, []float64{1})
+	m.AddCon("y", math.Inf(-1), 2, []int{y}, []float64{1})
+	m.AddCon("sum", 3, inf, []int{x, y}, []float64{1, 1})
+	blocks := [][]int{{0}, {1}}
+
+	res, err := lp.SolveDantzigWolfe(m, blocks, lp.DWOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Optimal || math.Abs(res.Obj-3) > 1e-6 {
+		t.Errorf("got objective %g, optimal %v, want 3", res.Obj, res.Optimal)
+	}
+	_, err = lp.SolveDantzigWolfe(m, blocks, lp.DWOptions{MaxIter: 1})
+	if want := "linking constraints not satisfied after 1 iterations"; err == nil || err.Error() != want {
+		t.Errorf("MaxIter: want error %q, got %v", want, err)
+	}
+
+	// Without the constraints of the blocks,
+	// the variables are not bounded above.
+	_, err = lp.SolveDantzigWolfe(m, nil, lp.DWOptions{})
+	if want := "variable 0 is in no block and has an infinite bound"; err == nil || err.Error() != want {
+		t.Errorf("unbounded block: want error %q, got %v", want, err)
+	}
+}
@@ -0,0 +1,137 @@
+package lp
+
+import (
+	"fmt"
+	"math"
+)
+
+// ToElasticDict returns a dictionary for the feasibility relaxation
+// in which the constraint of each of the given basic variables
+// may be violated using a new non-negative elastic variable e[k]
+//	x[labels[k]] = B[i] + sum_j A[i][j] x[NonBasic[j]] + e[k]
+// and the objective is to minimize the weighted violation
+//	max -sum_k weights[k] e[k].
+// Returns the labels of the elastic variables.
+//
+// Like the auxiliary variable of ToFeasDict, each elastic variable
+// of a row with B[i] < 0 is pivoted into the basic set.
+// Therefore the returned dictionary is feasible
+// if the constraints which are not relaxed are satisfied.
+// Returns an error if a label is not a basic variable
+// or if there is not one weight per label.
+func ToElasticDict(dict *Dict, labels []int, weights []float64) (elastic *Dict, evars []int, err error) {
+	if len(weights) != len(labels) {
+		return nil, nil, fmt.Errorf("%d labels but %d weights", len(labels), len(weights))
+	}
+	m, n := len(dict.Basic), len(dict.NonBasic)
+	elastic = NewDict(m, n+len(labels))
+	copy(elastic.Basic, dict.Basic)
+	copy(elastic.NonBasic, dict.NonBasic)
+	for i := range dict.A {
+		copy(elastic.A[i], dict.A[i])
+	}
+	copy(elastic.B, dict.B)
+
+	p := numVars(dict)
+	rows := make([]int, len(labels))
+	evars = make([]int, len(labels))
+	for k, lbl := range labels {
+		i, found := find(lbl, dict.Basic)
+		if !found {
+			return nil, nil, fmt.Errorf("variable %d is not basic", lbl)
+		}
+		rows[k] = i
+		evars[k] = p + k
+		elastic.NonBasic[n+k] = p + k
+		elastic.A[i][n+k] = 1
+		elastic.C[n+k] = -weights[k]
+	}
+
+	for k, i := range rows {
+		// Each elastic variable appears in one row,
+		// therefore the pivots do not affect each other.
+		if elastic.B[i] < 0 {
+			elastic = elastic.Pivot(n+k, i)
+		}
+	}
+	return elastic, evars, nil
+}
+
+// Relaxation is the solution of the feasibility relaxation of a model.
+type Relaxation struct {
+	X []float64
+	// Amount by which each constraint is violated.
+	Violations []float64
+	// Weighted sum of violations.
+	Total float64
+}
+
+// RelaxModel finds the solution of a model which minimizes
+// the weighted sum of the violations of its constraints.
+// Constraints with zero weight and the bounds of variables must be satisfied.
+// If weights is nil, every constraint has weight one.
+// The objective of the model is ignored.
+// Returns an error if the constraints which cannot be violated are infeasible.
+func RelaxModel(m *Model, weights []float64) (*Relaxation, error) {
+	return RelaxModelEps(m, weights, DefaultEps)
+}
+
+func RelaxModelEps(m *Model, weights []float64, eps float64) (*Relaxation, error) {
+	if weights == nil {
+		weights = make([]float64, len(m.Cons))
+		for i := range weights {
+			weights[i] = 1
+		}
+	}
+	if len(weights) != len(m.Cons) {
+		return nil, fmt.Errorf("have %d weights, want %d constraints", len(weights), len(m.Cons))
+	}
+
+	// Remove the objective.
+	zero := *m
+	zero.Const = 0
+	zero.Vars = make([]Var, len(m.Vars))
+	for j, v := range m.Vars {
+		zero.Vars[j] = Var{Lower: v.Lower, Upper: v.Upper}
+	}
+	l := m.layout()
+	var (
+		labels []int
+		ws     []float64
+		cons   []int
+	)
+	for i, w := range weights {
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, fmt.Errorf("constraint %d: invalid weight %g", i, w)
+		}
+		if w == 0 {
+			continue
+		}
+		for _, lbl := range []int{l.upper[i], l.lower[i]} {
+			if lbl >= 0 {
+				labels = append(labels, lbl)
+				ws = append(ws, w)
+				cons = append(cons, i)
+			}
+		}
+	}
+
+	dict, evars, err := ToElasticDict(zero.Dict(), labels, ws)
+	if err != nil {
+		return nil, err
+	}
+	final, err := SolveEps(dict, eps)
+	if err != nil {
+		return nil, err
+	}
+	vals := final.values()
+	r := &Relaxation{
+		X:          zero.Soln(final).X,
+		Violations: make([]float64, len(m.Cons)),
+		Total:      -final.Obj(),
+	}
+	for k, lbl := range evars {
+		r.Violations[cons[k]] += vals[lbl]
+	}
+	return r, nil
+}
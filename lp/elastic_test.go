@@ -0,0 +1,128 @@
+package lp_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/jvlmdr/golp/lp"
+)
+
+func ExampleRelaxModel() {
+	inf := math.Inf(1)
+	m := lp.NewModel()
+	x := m.AddVar("x", 0, 0, inf)
+	y := m.AddVar("y", 0, 0, inf)
+	// x + y >= 4
+	// x + 2 y <= 2
+	// x <= 1
+	m.AddCon("sum", 4, inf, []int{x, y}, []float64{1, 1})
+	m.AddCon("cap", math.Inf(-1), 2, []int{x, y}, []float64{1, 2})
+	m.AddCon("lim", math.Inf(-1), 1, []int{x}, []float64{1})
+
+	r, err := lp.RelaxModel(m, []float64{1, 1, 10})
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+	fmt.Printf("%.6g at %.6g, violations %.6g\n", r.Total, r.X, r.Violations)
+	// Output:
+	// 2.5 at [1 0.5], violations [2.5 0 0]
+}
+
+func TestRelaxModel(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	inf := math.Inf(1)
+	for trial := 0; trial < 200; trial++ {
+		n := 1 + r.Intn(4)
+		m := lp.NewModel()
+		for j := 0; j < n; j++ {
+			lo := float64(r.Intn(3) - 1)
+			m.AddVar("", float64(r.Intn(5)), lo, lo+float64(r.Intn(5)))
+		}
+		var weights []float64
+		for i := 1 + r.Intn(5); i > 0; i-- {
+			var con lp.Con
+			for j := 0; j < n; j++ {
+				if r.Intn(2) == 0 {
+					con.Vars = append(con.Vars, j)
+					con.Coeffs = append(con.Coeffs, float64(r.Intn(7)-3))
+				}
+			}
+			lo, hi := float64(r.Intn(11)-5), inf
+			switch r.Intn(3) {
+			case 0:
+				lo, hi = math.Inf(-1), lo
+			case 1:
+				hi = lo
+			}
+			m.AddCon("", lo, hi, con.Vars, con.Coeffs)
+			weights = append(weights, float64(r.Intn(4)))
+		}
+
+		// Solve the relaxation explicitly
+		//	min sum_i w[i] (p[i] + q[i])
+		//	s.t. lower <= a x + p - q <= upper.
+		want := lp.NewModel()
+		want.Min = true
+		for _, v := range m.Vars {
+			want.AddVar("", 0, v.Lower, v.Upper)
+		}
+		for i, con := range m.Cons {
+			vars := append(append([]int(nil), con.Vars...), n+2*i, n+2*i+1)
+			coeffs := append(append([]float64(nil), con.Coeffs...), 1, -1)
+			hi := inf
+			if weights[i] == 0 {
+				hi = 0
+			}
+			want.AddVar("", weights[i], 0, hi)
+			want.AddVar("", weights[i], 0, hi)
+			want.AddCon("", con.Lower, con.Upper, vars, coeffs)
+		}
+		s, wantErr := lp.SolveModel(want)
+
+		got, err := lp.RelaxModel(m, weights)
+		if (err == nil) != (wantErr == nil) {
+			t.Fatalf("trial %d: got error %v, want %v", trial, err, wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if math.Abs(got.Total-s.Obj) > 1e-6 {
+			t.Errorf("trial %d: got total %g, want %g", trial, got.Total, s.Obj)
+		}
+		var total float64
+		for i, con := range m.Cons {
+			act := con.Activity(got.X)
+			viol := math.Max(0, math.Max(con.Lower-act, act-con.Upper))
+			if math.Abs(viol-got.Violations[i]) > 1e-6 {
+				t.Errorf("trial %d: constraint %d: got violation %g, want %g", trial, i, got.Violations[i], viol)
+			}
+			total += weights[i] * viol
+		}
+		if math.Abs(total-got.Total) > 1e-6 {
+			t.Errorf("trial %d: total %g, sum of violations %g", trial, got.Total, total)
+		}
+		for j, v := range m.Vars {
+			if x := got.X[j]; x < v.Lower-1e-6 || x > v.Upper+1e-6 {
+				t.Errorf("trial %d: variable %d: %g not in [%g, %g]", trial, j, x, v.Lower, v.Upper)
+			}
+		}
+	}
+}
+
+func TestToElasticDict_notBasic(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	dict := randBoundedDict(r, 2, 3)
+	// Labels 0, 1, 2 are non-basic.
+	if _, _, err := lp.ToElasticDict(dict, []int{3, 0}, []float64{1, 1}); err == nil {
+		t.Error("expected error for non-basic label")
+	}
+	if _, _, err := lp.ToElasticDict(dict, []int{3, 4}, []float64{1}); err == nil {
+		t.Error("expected error for missing weight")
+	}
+	if _, _, err := lp.ToElasticDict(dict, []int{3, 4}, []float64{1, 1}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,667 @@
+package lp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// EncodingVersion is the version of the JSON and binary encodings
+// of dictionaries, models and results.
+// It is written with every encoded value and
+// decoding fails if the version is not supported.
+const EncodingVersion = 1
+
+// A number in JSON which may be infinite or NaN.
+// Non-finite values are encoded as the strings "Infinity", "-Infinity" and "NaN".
+type jsonFloat float64
+
+func (x jsonFloat) MarshalJSON() ([]byte, error) {
+	f := float64(x)
+	switch {
+	case math.IsInf(f, 1):
+		return []byte(`"Infinity"`), nil
+	case math.IsInf(f, -1):
+		return []byte(`"-Infinity"`), nil
+	case math.IsNaN(f):
+		return []byte(`"NaN"`), nil
+	}
+	return strconv.AppendFloat(nil, f, 'g', -1, 64), nil
+}
+
+func (x *jsonFloat) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case `"Infinity"`:
+		*x = jsonFloat(math.Inf(1))
+		return nil
+	case `"-Infinity"`:
+		*x = jsonFloat(math.Inf(-1))
+		return nil
+	case `"NaN"`:
+		*x = jsonFloat(math.NaN())
+		return nil
+	}
+	var f float64
+	if err := json.Unmarshal(data, &f); err != nil {
+		return err
+	}
+	*x = jsonFloat(f)
+	return nil
+}
+
+func toJSONFloats(xs []float64) []jsonFloat {
+	if xs == nil {
+		return nil
+	}
+	ys := make([]jsonFloat, len(xs))
+	for i, x := range xs {
+		ys[i] = jsonFloat(x)
+	}
+	return ys
+}
+
+func fromJSONFloats(xs []jsonFloat) []float64 {
+	if xs == nil {
+		return nil
+	}
+	ys := make([]float64, len(xs))
+	for i, x := range xs {
+		ys[i] = float64(x)
+	}
+	return ys
+}
+
+func checkVersion(v int) error {
+	if v != EncodingVersion {
+		return fmt.Errorf("unsupported encoding version %d", v)
+	}
+	return nil
+}
+
+type dictJSON struct {
+	Version  int           `json:"version"`
+	Basic    []int         `json:"basic"`
+	NonBasic []int         `json:"nonbasic"`
+	A        [][]jsonFloat `json:"a"`
+	B        []jsonFloat   `json:"b"`
+	C        []jsonFloat   `json:"c"`
+	D        jsonFloat     `json:"d"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (dict *Dict) MarshalJSON() ([]byte, error) {
+	v := dictJSON{
+		Version:  EncodingVersion,
+		Basic:    dict.Basic,
+		NonBasic: dict.NonBasic,
+		A:        make([][]jsonFloat, len(dict.A)),
+		B:        toJSONFloats(dict.B),
+		C:        toJSONFloats(dict.C),
+		D:        jsonFloat(dict.D),
+	}
+	for i, ai := range dict.A {
+		v.A[i] = toJSONFloats(ai)
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (dict *Dict) UnmarshalJSON(data []byte) error {
+	var v dictJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if err := checkVersion(v.Version); err != nil {
+		return err
+	}
+	*dict = Dict{
+		Basic:    v.Basic,
+		NonBasic: v.NonBasic,
+		A:        make([][]float64, len(v.A)),
+		B:        fromJSONFloats(v.B),
+		C:        fromJSONFloats(v.C),
+		D:        float64(v.D),
+	}
+	for i, ai := range v.A {
+		dict.A[i] = fromJSONFloats(ai)
+	}
+	return nil
+}
+
+type modelJSON struct {
+	Version int       `json:"version"`
+	Name    string    `json:"name,omitempty"`
+	Min     bool      `json:"min"`
+	Const   jsonFloat `json:"const"`
+	Vars    []varJSON `json:"vars"`
+	Cons    []conJSON `json:"cons"`
+}
+
+type varJSON struct {
+	Name  string    `json:"name,omitempty"`
+	Obj   jsonFloat `json:"obj"`
+	Lower jsonFloat `json:"lower"`
+	Upper jsonFloat `json:"upper"`
+	Int   bool      `json:"int,omitempty"`
+}
+
+type conJSON struct {
+	Name   string      `json:"name,omitempty"`
+	Vars   []int       `json:"vars"`
+	Coeffs []jsonFloat `json:"coeffs"`
+	Lower  jsonFloat   `json:"lower"`
+	Upper  jsonFloat   `json:"upper"`
+}
+
+// MarshalJSON implements json.Marshaler.
+// Infinite bounds are encoded as the strings "Infinity" and "-Infinity".
+func (m *Model) MarshalJSON() ([]byte, error) {
+	v := modelJSON{
+		Version: EncodingVersion,
+		Name:    m.Name,
+		Min:     m.Min,
+		Const:   jsonFloat(m.Const),
+		Vars:    make([]varJSON, len(m.Vars)),
+		Cons:    make([]conJSON, len(m.Cons)),
+	}
+	for j, x := range m.Vars {
+		v.Vars[j] = varJSON{x.Name, jsonFloat(x.Obj), jsonFloat(x.Lower), jsonFloat(x.Upper), x.Int}
+	}
+	for i, con := range m.Cons {
+		v.Cons[i] = conJSON{con.Name, con.Vars, toJSONFloats(con.Coeffs), jsonFloat(con.Lower), jsonFloat(con.Upper)}
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (m *Model) UnmarshalJSON(data []byte) error {
+	var v modelJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if err := checkVersion(v.Version); err != nil {
+		return err
+	}
+	*m = Model{Name: v.Name, Min: v.Min, Const: float64(v.Const)}
+	if v.Vars != nil {
+		m.Vars = make([]Var, len(v.Vars))
+	}
+	for j, x := range v.Vars {
+		m.Vars[j] = Var{x.Name, float64(x.Obj), float64(x.Lower), float64(x.Upper), x.Int}
+	}
+	if v.Cons != nil {
+		m.Cons = make([]Con, len(v.Cons))
+	}
+	for i, con := range v.Cons {
+		if len(con.Vars) != len(con.Coeffs) {
+			return fmt.Errorf("constraint %d: %d variables and %d coefficients", i, len(con.Vars), len(con.Coeffs))
+		}
+		for _, j := range con.Vars {
+			if j < 0 || j >= len(m.Vars) {
+				return fmt.Errorf("constraint %d: no variable %d", i, j)
+			}
+		}
+		m.Cons[i] = Con{con.Name, con.Vars, fromJSONFloats(con.Coeffs), float64(con.Lower), float64(con.Upper)}
+	}
+	return nil
+}
+
+type modelSolnJSON struct {
+	Version  int         `json:"version"`
+	X        []jsonFloat `json:"x"`
+	Obj      jsonFloat   `json:"obj"`
+	Duals    []jsonFloat `json:"duals"`
+	RedCosts []jsonFloat `json:"red_costs"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s *ModelSoln) MarshalJSON() ([]byte, error) {
+	return json.Marshal(modelSolnJSON{
+		Version:  EncodingVersion,
+		X:        toJSONFloats(s.X),
+		Obj:      jsonFloat(s.Obj),
+		Duals:    toJSONFloats(s.Duals),
+		RedCosts: toJSONFloats(s.RedCosts),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ModelSoln) UnmarshalJSON(data []byte) error {
+	var v modelSolnJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if err := checkVersion(v.Version); err != nil {
+		return err
+	}
+	*s = ModelSoln{
+		X:        fromJSONFloats(v.X),
+		Obj:      float64(v.Obj),
+		Duals:    fromJSONFloats(v.Duals),
+		RedCosts: fromJSONFloats(v.RedCosts),
+	}
+	return nil
+}
+
+type intResultJSON struct {
+	Version   int         `json:"version"`
+	Dict      *Dict       `json:"dict"`
+	X         []jsonFloat `json:"x"`
+	Obj       jsonFloat   `json:"obj"`
+	Bound     jsonFloat   `json:"bound"`
+	Gap       jsonFloat   `json:"gap"`
+	Nodes     int         `json:"nodes"`
+	CutRounds int         `json:"cut_rounds"`
+}
+
+// MarshalJSON implements json.Marshaler.
+// An infinite gap is encoded as the string "Infinity".
+func (res *IntResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(intResultJSON{
+		Version:   EncodingVersion,
+		Dict:      res.Dict,
+		X:         toJSONFloats(res.X),
+		Obj:       jsonFloat(res.Obj),
+		Bound:     jsonFloat(res.Bound),
+		Gap:       jsonFloat(res.Gap),
+		Nodes:     res.Nodes,
+		CutRounds: res.CutRounds,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (res *IntResult) UnmarshalJSON(data []byte) error {
+	var v intResultJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if err := checkVersion(v.Version); err != nil {
+		return err
+	}
+	*res = IntResult{
+		Dict:      v.Dict,
+		X:         fromJSONFloats(v.X),
+		Obj:       float64(v.Obj),
+		Bound:     float64(v.Bound),
+		Gap:       float64(v.Gap),
+		Nodes:     v.Nodes,
+		CutRounds: v.CutRounds,
+	}
+	return nil
+}
+
+// Binary encoding.
+//
+// The binary encoding of a dictionary, model or result begins with
+// the encoding version and a byte identifying the type.
+// Integers are varints and numbers are 8 bytes (little endian).
+// The encodings implement encoding.BinaryMarshaler and
+// are therefore used by encoding/gob.
+
+// Types of values in the binary encoding.
+const (
+	binaryDict      = 'D'
+	binaryModel     = 'M'
+	binaryModelSoln = 'S'
+	binaryIntResult = 'R'
+)
+
+type binWriter struct {
+	buf bytes.Buffer
+	tmp [binary.MaxVarintLen64]byte
+}
+
+// Writes the version and type.
+func (w *binWriter) header(kind byte) {
+	w.buf.WriteByte(EncodingVersion)
+	w.buf.WriteByte(kind)
+}
+
+func (w *binWriter) int(x int) {
+	k := binary.PutVarint(w.tmp[:], int64(x))
+	w.buf.Write(w.tmp[:k])
+}
+
+func (w *binWriter) ints(xs []int) {
+	for _, x := range xs {
+		w.int(x)
+	}
+}
+
+func (w *binWriter) float(x float64) {
+	binary.LittleEndian.PutUint64(w.tmp[:8], math.Float64bits(x))
+	w.buf.Write(w.tmp[:8])
+}
+
+func (w *binWriter) floats(xs []float64) {
+	for _, x := range xs {
+		w.float(x)
+	}
+}
+
+func (w *binWriter) bool(x bool) {
+	var b byte
+	if x {
+		b = 1
+	}
+	w.buf.WriteByte(b)
+}
+
+func (w *binWriter) string(s string) {
+	w.int(len(s))
+	w.buf.WriteString(s)
+}
+
+// Writes a slice which may be nil, preceded by its length.
+// A nil slice has length -1.
+func (w *binWriter) slice(xs []float64) {
+	if xs == nil {
+		w.int(-1)
+		return
+	}
+	w.int(len(xs))
+	w.floats(xs)
+}
+
+// Reads values from a binary encoding.
+// After the first error, all values are zero.
+type binReader struct {
+	buf []byte
+	err error
+}
+
+var errShortBinary = errors.New("binary encoding is too short")
+
+func (r *binReader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+	r.buf = nil
+}
+
+func (r *binReader) int() int {
+	if r.err != nil {
+		return 0
+	}
+	x, k := binary.Varint(r.buf)
+	if k <= 0 {
+		r.fail(errShortBinary)
+		return 0
+	}
+	r.buf = r.buf[k:]
+	return int(x)
+}
+
+// Reads a length, which must be non-negative and
+// no more than the number of bytes remaining divided by size.
+func (r *binReader) len(size int) int {
+	n := r.int()
+	if n < 0 || n > len(r.buf)/size {
+		r.fail(errShortBinary)
+		return 0
+	}
+	return n
+}
+
+func (r *binReader) ints(n int) []int {
+	xs := make([]int, n)
+	for i := range xs {
+		xs[i] = r.int()
+	}
+	return xs
+}
+
+func (r *binReader) float() float64 {
+	if len(r.buf) < 8 {
+		r.fail(errShortBinary)
+		return 0
+	}
+	x := math.Float64frombits(binary.LittleEndian.Uint64(r.buf))
+	r.buf = r.buf[8:]
+	return x
+}
+
+func (r *binReader) floats(n int) []float64 {
+	xs := make([]float64, n)
+	for i := range xs {
+		xs[i] = r.float()
+	}
+	return xs
+}
+
+// Reads a slice written by binWriter.slice.
+func (r *binReader) slice() []float64 {
+	n := r.int()
+	if n == -1 {
+		return nil
+	}
+	if n < 0 || n > len(r.buf)/8 {
+		r.fail(errShortBinary)
+		return nil
+	}
+	return r.floats(n)
+}
+
+func (r *binReader) bool() bool {
+	if len(r.buf) < 1 {
+		r.fail(errShortBinary)
+		return false
+	}
+	b := r.buf[0]
+	r.buf = r.buf[1:]
+	return b != 0
+}
+
+func (r *binReader) string() string {
+	n := r.len(1)
+	s := string(r.buf[:n])
+	r.buf = r.buf[n:]
+	return s
+}
+
+// Reads the version and type.
+func (r *binReader) header(kind byte) {
+	if len(r.buf) < 2 {
+		r.fail(errShortBinary)
+		return
+	}
+	if err := checkVersion(int(r.buf[0])); err != nil {
+		r.fail(err)
+		return
+	}
+	if r.buf[1] != kind {
+		r.fail(fmt.Errorf("binary encoding has type %q, want %q", r.buf[1], kind))
+		return
+	}
+	r.buf = r.buf[2:]
+}
+
+// Checks that all input was consumed.
+func (r *binReader) finish() error {
+	if r.err == nil && len(r.buf) > 0 {
+		r.err = errors.New("extra data after binary encoding")
+	}
+	return r.err
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (dict *Dict) MarshalBinary() ([]byte, error) {
+	m, n := len(dict.Basic), len(dict.NonBasic)
+	w := new(binWriter)
+	w.buf.Grow(2 + 8*(m+1)*(n+1) + 2*(m+n+2))
+	w.header(binaryDict)
+	w.int(m)
+	w.int(n)
+	w.ints(dict.Basic)
+	w.ints(dict.NonBasic)
+	for _, ai := range dict.A {
+		w.floats(ai)
+	}
+	w.floats(dict.B)
+	w.floats(dict.C)
+	w.float(dict.D)
+	return w.buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (dict *Dict) UnmarshalBinary(data []byte) error {
+	r := &binReader{buf: data}
+	r.header(binaryDict)
+	m, n := r.len(1), r.len(1)
+	if r.err == nil && m*n > len(r.buf)/8 {
+		r.fail(errShortBinary)
+	}
+	if r.err != nil {
+		return r.err
+	}
+	d := &Dict{Basic: r.ints(m), NonBasic: r.ints(n), A: make([][]float64, m)}
+	for i := range d.A {
+		d.A[i] = r.floats(n)
+	}
+	d.B = r.floats(m)
+	d.C = r.floats(n)
+	d.D = r.float()
+	if err := r.finish(); err != nil {
+		return err
+	}
+	*dict = *d
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (m *Model) MarshalBinary() ([]byte, error) {
+	w := new(binWriter)
+	w.header(binaryModel)
+	w.string(m.Name)
+	w.bool(m.Min)
+	w.float(m.Const)
+	w.int(len(m.Vars))
+	for _, v := range m.Vars {
+		w.string(v.Name)
+		w.floats([]float64{v.Obj, v.Lower, v.Upper})
+		w.bool(v.Int)
+	}
+	w.int(len(m.Cons))
+	for _, con := range m.Cons {
+		if len(con.Vars) != len(con.Coeffs) {
+			return nil, fmt.Errorf("constraint %q: %d variables and %d coefficients", con.Name, len(con.Vars), len(con.Coeffs))
+		}
+		w.string(con.Name)
+		w.int(len(con.Vars))
+		w.ints(con.Vars)
+		w.floats(con.Coeffs)
+		w.floats([]float64{con.Lower, con.Upper})
+	}
+	return w.buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (m *Model) UnmarshalBinary(data []byte) error {
+	r := &binReader{buf: data}
+	r.header(binaryModel)
+	d := &Model{Name: r.string(), Min: r.bool(), Const: r.float()}
+	if p := r.len(1); p > 0 {
+		d.Vars = make([]Var, p)
+		for j := range d.Vars {
+			v := &d.Vars[j]
+			v.Name = r.string()
+			v.Obj, v.Lower, v.Upper = r.float(), r.float(), r.float()
+			v.Int = r.bool()
+		}
+	}
+	if k := r.len(1); k > 0 {
+		d.Cons = make([]Con, k)
+		for i := range d.Cons {
+			con := &d.Cons[i]
+			con.Name = r.string()
+			if t := r.len(1); t > 0 {
+				con.Vars = r.ints(t)
+				con.Coeffs = r.floats(t)
+			}
+			for _, j := range con.Vars {
+				if j < 0 || j >= len(d.Vars) {
+					r.fail(fmt.Errorf("constraint %d: no variable %d", i, j))
+				}
+			}
+			con.Lower, con.Upper = r.float(), r.float()
+		}
+	}
+	if err := r.finish(); err != nil {
+		return err
+	}
+	*m = *d
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (s *ModelSoln) MarshalBinary() ([]byte, error) {
+	w := new(binWriter)
+	w.header(binaryModelSoln)
+	w.slice(s.X)
+	w.float(s.Obj)
+	w.slice(s.Duals)
+	w.slice(s.RedCosts)
+	return w.buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *ModelSoln) UnmarshalBinary(data []byte) error {
+	r := &binReader{buf: data}
+	r.header(binaryModelSoln)
+	d := &ModelSoln{X: r.slice(), Obj: r.float(), Duals: r.slice(), RedCosts: r.slice()}
+	if err := r.finish(); err != nil {
+		return err
+	}
+	*s = *d
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+// The dictionary, if any, is included using its binary encoding.
+func (res *IntResult) MarshalBinary() ([]byte, error) {
+	w := new(binWriter)
+	w.header(binaryIntResult)
+	w.bool(res.Dict != nil)
+	if res.Dict != nil {
+		dict, err := res.Dict.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		w.int(len(dict))
+		w.buf.Write(dict)
+	}
+	w.slice(res.X)
+	w.floats([]float64{res.Obj, res.Bound, res.Gap})
+	w.ints([]int{res.Nodes, res.CutRounds})
+	return w.buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (res *IntResult) UnmarshalBinary(data []byte) error {
+	r := &binReader{buf: data}
+	r.header(binaryIntResult)
+	d := new(IntResult)
+	if r.bool() {
+		n := r.len(1)
+		if r.err != nil {
+			return r.err
+		}
+		d.Dict = new(Dict)
+		if err := d.Dict.UnmarshalBinary(r.buf[:n]); err != nil {
+			return err
+		}
+		r.buf = r.buf[n:]
+	}
+	d.X = r.slice()
+	d.Obj, d.Bound, d.Gap = r.float(), r.float(), r.float()
+	d.Nodes, d.CutRounds = r.int(), r.int()
+	if err := r.finish(); err != nil {
+		return err
+	}
+	*res = *d
+	return nil
+}
@@ -0,0 +1,166 @@
+package lp_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jvlmdr/golp/lp"
+)
+
+func ExampleModel_MarshalJSON() {
+	m := lp.NewModel()
+	x := m.AddVar("x", 1, 0, 4)
+	y := m.AddIntVar("y", 2, 0, 5)
+	m.AddCon("sum", 1, math.Inf(1), []int{x, y}, []float64{1, 1})
+	data, err := json.Marshal(m)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+	fmt.Println(string(data))
+	// Output:
+	// {"version":1,"min":false,"const":0,"vars":[{"name":"x","obj":1,"lower":0,"upper":4},{"name":"y","obj":2,"lower":0,"upper":5,"int":true}],"cons":[{"name":"sum","vars":[0,1],"coeffs":[1,1],"lower":1,"upper":"Infinity"}]}
+}
+
+// Encodes and decodes a value using JSON and gob.
+func roundTrips(t *testing.T, v, json1, gob1 interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, json1); err != nil {
+		t.Fatalf("%v:\n%s", err, data)
+	}
+	var b bytes.Buffer
+	if err := gob.NewEncoder(&b).Encode(v); err != nil {
+		t.Fatal(err)
+	}
+	if err := gob.NewDecoder(&b).Decode(gob1); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 50; trial++ {
+		dict := randColoradoDict(r, 1+r.Intn(5), 1+r.Intn(5))
+		var dj, dg lp.Dict
+		roundTrips(t, dict, &dj, &dg)
+		if !reflect.DeepEqual(&dj, dict) || !reflect.DeepEqual(&dg, dict) {
+			t.Fatalf("dict differs:\njson %+v\ngob  %+v\nwant %+v", dj, dg, *dict)
+		}
+
+		m := randFormatModel(r)
+		var mj, mg lp.Model
+		roundTrips(t, m, &mj, &mg)
+		if !reflect.DeepEqual(&mj, m) || !reflect.DeepEqual(&mg, m) {
+			t.Fatalf("model differs:\njson %+v\ngob  %+v\nwant %+v", mj, mg, *m)
+		}
+
+		soln := &lp.ModelSoln{X: []float64{1, 2}, Obj: r.NormFloat64(), Duals: []float64{0.5}, RedCosts: []float64{0, -1}}
+		var sj, sg lp.ModelSoln
+		roundTrips(t, soln, &sj, &sg)
+		if !reflect.DeepEqual(&sj, soln) || !reflect.DeepEqual(&sg, soln) {
+			t.Fatalf("soln differs:\njson %+v\ngob  %+v\nwant %+v", sj, sg, *soln)
+		}
+	}
+
+	dict := randIntDict(r, 3, 3)
+	res, err := lp.SolveIntOpts(dict, lp.IntOptions{NodeLimit: 1, CutRounds: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Without a dictionary or incumbent.
+	empty := &lp.IntResult{Obj: math.Inf(-1), Bound: 3, Gap: math.Inf(1), Nodes: 1}
+	for _, res := range []*lp.IntResult{res, empty} {
+		var rj, rg lp.IntResult
+		roundTrips(t, res, &rj, &rg)
+		if !reflect.DeepEqual(&rj, res) || !reflect.DeepEqual(&rg, res) {
+			t.Fatalf("result differs:\njson %+v\ngob  %+v\nwant %+v", rj, rg, *res)
+		}
+	}
+}
+
+func TestEncoding_version(t *testing.T) {
+	data, err := json.Marshal(lp.NewDict(1, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = bytes.Replace(data, []byte(`"version":1`), []byte(`"version":2`), 1)
+	var dict lp.Dict
+	if err := json.Unmarshal(data, &dict); err == nil || !strings.Contains(err.Error(), "version 2") {
+		t.Errorf("want version error, got %v", err)
+	}
+
+	bin, err := lp.NewModel().MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dict.UnmarshalBinary(bin); err == nil {
+		t.Error("decoded model as dictionary")
+	}
+	bin, err = lp.NewDict(2, 3).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dict.UnmarshalBinary(bin[:len(bin)-1]); err == nil {
+		t.Error("decoded truncated dictionary")
+	}
+
+	bin, err = (&lp.ModelSoln{X: []float64{1}}).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res lp.IntResult
+	if err := res.UnmarshalBinary(bin); err == nil {
+		t.Error("decoded solution as result")
+	}
+	bin, err = (&lp.IntResult{Dict: lp.NewDict(2, 3), X: []float64{1, 2}}).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n := 0; n < len(bin); n++ {
+		if err := res.UnmarshalBinary(bin[:n]); err == nil {
+			t.Errorf("decoded result truncated to %d bytes", n)
+		}
+	}
+}
+
+func TestEncoding_modelVars(t *testing.T) {
+	for _, vars := range [][]int{{0, 2}, {-1, 1}} {
+		m := lp.NewModel()
+		m.AddVar("x", 1, 0, 1)
+		m.AddVar("y", 1, 0, 1)
+		m.Cons = append(m.Cons, lp.Con{Vars: vars, Coeffs: []float64{1, 1}, Upper: 1})
+
+		data, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var mj lp.Model
+		if err := json.Unmarshal(data, &mj); err == nil || !strings.Contains(err.Error(), "no variable") {
+			t.Errorf("vars %v: json: want variable error, got %v", vars, err)
+		}
+		bin, err := m.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var mb lp.Model
+		if err := mb.UnmarshalBinary(bin); err == nil || !strings.Contains(err.Error(), "no variable") {
+			t.Errorf("vars %v: binary: want variable error, got %v", vars, err)
+		}
+	}
+
+	data := []byte(`{"version":1,"vars":[{"name":"x"}],"cons":[{"vars":[0],"coeffs":[1,2]}]}`)
+	var m lp.Model
+	if err := json.Unmarshal(data, &m); err == nil {
+		t.Error("decoded constraint with extra coefficient")
+	}
+}
@@ -0,0 +1,294 @@
+package lp
+
+import "math"
+
+// Heuristic attempts to find an integer solution of the original problem
+// given the solution of a relaxation.
+// The relaxation may contain additional constraints and variables
+// but all variables in the original dictionary must be present.
+//
+// The integer solver calls heuristics with dictionaries labelled 0, ..., p-1
+// where p is the number of variables in the original dictionary
+// (followed by the labels of any added variables).
+// The solution is indexed by label
+// and contains the variables of the original dictionary.
+type Heuristic interface {
+	Find(orig, relax *Dict, eps float64) (x []float64, ok bool)
+}
+
+// Returns the solution obtained by setting the non-basic variables
+// of the dictionary to their values in x.
+// Also returns the objective.
+func (dict *Dict) point(x []float64) (soln []float64, obj float64) {
+	soln = make([]float64, numVars(dict))
+	obj = dict.D
+	for j, lbl := range dict.NonBasic {
+		soln[lbl] = x[lbl]
+		obj += dict.C[j] * x[lbl]
+	}
+	for i, lbl := range dict.Basic {
+		v := dict.B[i]
+		for j, nb := range dict.NonBasic {
+			v += dict.A[i][j] * x[nb]
+		}
+		soln[lbl] = v
+	}
+	return soln, obj
+}
+
+// Returns true if all variables are non-negative and integer.
+func isFeasInt(x []float64, eps float64) bool {
+	for _, v := range x {
+		if v < -eps || math.Abs(distInt(v)) > eps {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns a copy of x with every element rounded to the nearest integer.
+func roundAll(x []float64) []float64 {
+	y := make([]float64, len(x))
+	for i, v := range x {
+		y[i] = math.Floor(v + 0.5)
+	}
+	return y
+}
+
+// Rounding rounds every non-basic variable of the original dictionary
+// to the nearest integer and accepts the result if it is feasible.
+type Rounding struct{}
+
+func (Rounding) Find(orig, relax *Dict, eps float64) ([]float64, bool) {
+	x := relax.values()
+	y := make([]float64, len(x))
+	for _, lbl := range orig.NonBasic {
+		y[lbl] = math.Floor(x[lbl] + 0.5)
+	}
+	soln, _ := orig.point(y)
+	if !isFeasInt(soln, eps) {
+		return nil, false
+	}
+	return soln, true
+}
+
+// SimpleRounding rounds every fractional non-basic variable
+// of the original dictionary in a direction which can not decrease
+// any of its basic variables.
+// It fails if a variable can not be rounded in either direction.
+type SimpleRounding struct{}
+
+func (SimpleRounding) Find(orig, relax *Dict, eps float64) ([]float64, bool) {
+	x := relax.values()
+	y := make([]float64, len(x))
+	for j, lbl := range orig.NonBasic {
+		v := x[lbl]
+		if math.Abs(distInt(v)) <= eps {
+			y[lbl] = math.Floor(v + 0.5)
+			continue
+		}
+		// Count rows which would decrease in each direction.
+		var down, up bool
+		for i := range orig.Basic {
+			if orig.A[i][j] > eps {
+				down = true
+			} else if orig.A[i][j] < -eps {
+				up = true
+			}
+		}
+		switch {
+		case !up:
+			y[lbl] = math.Ceil(v)
+		case !down:
+			y[lbl] = math.Floor(v)
+		default:
+			return nil, false
+		}
+	}
+	soln, _ := orig.point(y)
+	if !isFeasInt(soln, eps) {
+		return nil, false
+	}
+	return soln, true
+}
+
+// Diving repeatedly bounds the least fractional basic variable
+// to its nearest integer and re-solves the relaxation using the dual simplex method.
+// If the bound makes the relaxation infeasible, the opposite bound is tried once.
+type Diving struct {
+	// Maximum number of bounds to add.
+	// If zero, there is no limit.
+	MaxDepth int
+}
+
+func (h Diving) Find(orig, relax *Dict, eps float64) ([]float64, bool) {
+	dict := relax
+	for depth := 0; h.MaxDepth == 0 || depth < h.MaxDepth; depth++ {
+		// Find least fractional basic variable.
+		var (
+			found bool
+			arg   int
+			min   float64
+		)
+		for i := range dict.Basic {
+			dist := math.Abs(distInt(dict.B[i]))
+			if dist <= eps {
+				continue
+			}
+			if !found || dist < min {
+				found, arg, min = true, i, dist
+			}
+		}
+		if !found {
+			// Solution is integer.
+			return roundAll(dict.values()[:numVars(orig)]), true
+		}
+
+		lbl, val := dict.Basic[arg], dict.B[arg]
+		down := Cut{Labels: []int{lbl}, Coeffs: []float64{-1}, Const: math.Floor(val)}
+		up := Cut{Labels: []int{lbl}, Coeffs: []float64{1}, Const: -math.Ceil(val)}
+		if mod1(val) > 0.5 {
+			down, up = up, down
+		}
+		next, infeas := pivotToFinalDualEps(AddCuts(dict, []Cut{down}), eps)
+		if infeas {
+			// Try the other direction.
+			next, infeas = pivotToFinalDualEps(AddCuts(dict, []Cut{up}), eps)
+			if infeas {
+				return nil, false
+			}
+		}
+		dict = next
+	}
+	return nil, false
+}
+
+// FeasPump alternates between rounding the solution of the relaxation
+// and projecting the rounded point back onto the relaxation
+// by minimizing the L1 distance to it.
+// It succeeds when the rounded point is feasible.
+type FeasPump struct {
+	// Maximum number of projections.
+	// If zero, a default of 20 is used.
+	MaxIter int
+}
+
+func (h FeasPump) Find(orig, relax *Dict, eps float64) ([]float64, bool) {
+	maxIter := h.MaxIter
+	if maxIter == 0 {
+		maxIter = 20
+	}
+	p := numVars(orig)
+
+	x := relax.values()
+	var prev []float64
+	for iter := 0; ; iter++ {
+		// Round non-basic variables of original problem.
+		y := make([]float64, p)
+		for _, lbl := range orig.NonBasic {
+			y[lbl] = math.Floor(x[lbl] + 0.5)
+		}
+		if prev != nil && equalAt(y, prev, orig.NonBasic) {
+			// Cycle detected: flip the variable furthest from the relaxation.
+			var arg int
+			var max float64
+			for _, lbl := range orig.NonBasic {
+				if d := math.Abs(x[lbl] - y[lbl]); d > max {
+					arg, max = lbl, d
+				}
+			}
+			if x[arg] > y[arg] {
+				y[arg]++
+			} else if y[arg] > 0 {
+				y[arg]--
+			}
+		}
+		soln, _ := orig.point(y)
+		if isFeasInt(soln, eps) {
+			return soln, true
+		}
+		if iter == maxIter {
+			return nil, false
+		}
+		prev = y
+
+		// Project onto relaxation.
+		dict, err := SolveEps(pumpDict(relax, orig.NonBasic, y), eps)
+		if err != nil {
+			return nil, false
+		}
+		x = dict.values()
+	}
+}
+
+// Returns true if x and y are equal for all labels.
+func equalAt(x, y []float64, labels []int) bool {
+	for _, lbl := range labels {
+		if x[lbl] != y[lbl] {
+			return false
+		}
+	}
+	return true
+}
+
+// Constructs the problem
+//	min  sum_{j: y[j] = 0} x[j] + sum_{j: y[j] > 0} d[j]
+//	s.t. d[j] >= x[j] - y[j],  d[j] >= y[j] - x[j]
+// subject to the constraints of the relaxation.
+// The auxiliary variables d are added to the non-basic set.
+func pumpDict(relax *Dict, labels []int, y []float64) *Dict {
+	m, n := len(relax.Basic), len(relax.NonBasic)
+	p := numVars(relax)
+	var aux []int
+	for _, lbl := range labels {
+		if y[lbl] > 0 {
+			aux = append(aux, lbl)
+		}
+	}
+	k := len(aux)
+
+	// Add new non-basic variable for each auxiliary variable.
+	dict := NewDict(m+2*k, n+k)
+	copy(dict.Basic, relax.Basic)
+	copy(dict.NonBasic, relax.NonBasic)
+	for i := 0; i < m; i++ {
+		copy(dict.A[i], relax.A[i])
+	}
+	copy(dict.B, relax.B)
+	for t := 0; t < k; t++ {
+		dict.NonBasic[n+t] = p + t
+	}
+
+	// Add a pair of rows for each auxiliary variable.
+	for t, lbl := range aux {
+		a, b := relax.express([]int{lbl}, []float64{1}, 0)
+		lo, hi := dict.A[m+2*t], dict.A[m+2*t+1]
+		// d - x + y >= 0
+		dict.Basic[m+2*t] = p + k + 2*t
+		dict.B[m+2*t] = y[lbl] - b
+		// d + x - y >= 0
+		dict.Basic[m+2*t+1] = p + k + 2*t + 1
+		dict.B[m+2*t+1] = b - y[lbl]
+		for j := 0; j < n; j++ {
+			lo[j], hi[j] = -a[j], a[j]
+		}
+		lo[n+t], hi[n+t] = 1, 1
+	}
+
+	// Objective is to minimize distance.
+	var zero []int
+	var ones []float64
+	for _, lbl := range labels {
+		if y[lbl] == 0 {
+			zero = append(zero, lbl)
+			ones = append(ones, -1)
+		}
+	}
+	c, d := relax.express(zero, ones, 0)
+	copy(dict.C, c)
+	dict.D = d
+	for t := 0; t < k; t++ {
+		dict.C[n+t] = -1
+	}
+	return dict
+}
@@ -0,0 +1,105 @@
+package lp_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/jvlmdr/golp/lp"
+)
+
+func ExampleHeuristic() {
+	dict := new(lp.Dict)
+	dict.NonBasic = []int{0, 1}
+	dict.Basic = []int{2, 3, 4}
+	// max_{x, y >= 0} x + 2 y
+	dict.C = []float64{1, 2}
+	// subject to
+	// -x + y <= 1,     x -  y +  1 >= 0
+	// 3x + 2y <= 12, -3x - 2y + 12 >= 0
+	// 2x + 3y <= 12, -2x - 3y + 12 >= 0
+	dict.A = make([][]float64, 3)
+	dict.B = make([]float64, 3)
+	dict.A[0], dict.B[0] = []float64{1, -1}, 1
+	dict.A[1], dict.B[1] = []float64{-3, -2}, 12
+	dict.A[2], dict.B[2] = []float64{-2, -3}, 12
+
+	opts := lp.IntOptions{
+		Heuristics: []lp.Heuristic{
+			lp.SimpleRounding{},
+			lp.Rounding{},
+			lp.Diving{},
+			lp.FeasPump{},
+		},
+		Progress: func(res lp.IntResult) {
+			fmt.Printf("incumbent %.6g at %.6g\n", res.Obj, res.X[:2])
+		},
+	}
+	res, err := lp.SolveIntOpts(dict, opts)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+	fmt.Printf("%.6g at %.6g\n", res.Obj, res.X[:2])
+	// Output:
+	// incumbent 5 at [3 1]
+	// incumbent 6 at [2 2]
+	// 6 at [2 2]
+}
+
+func TestHeuristic(t *testing.T) {
+	eps := lp.DefaultEps
+	heurs := []struct {
+		Name string
+		Heur lp.Heuristic
+	}{
+		{"Rounding", lp.Rounding{}},
+		{"SimpleRounding", lp.SimpleRounding{}},
+		{"Diving", lp.Diving{}},
+		{"FeasPump", lp.FeasPump{}},
+	}
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 50; trial++ {
+		orig := randIntDict(r, 3, 3)
+		relax, err := lp.Solve(orig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		best := bruteForceInt(orig)
+		for _, h := range heurs {
+			x, ok := h.Heur.Find(orig, relax, eps)
+			if !ok {
+				continue
+			}
+			if len(x) != len(orig.Basic)+len(orig.NonBasic) {
+				t.Errorf("trial %d, %s: got %d values", trial, h.Name, len(x))
+				continue
+			}
+			for lbl, v := range x {
+				if math.Abs(v-math.Floor(v+0.5)) > eps {
+					t.Errorf("trial %d, %s: x[%d] = %g not integer", trial, h.Name, lbl, v)
+				}
+			}
+			obj := orig.D
+			for j, lbl := range orig.NonBasic {
+				obj += orig.C[j] * x[lbl]
+			}
+			for i, lbl := range orig.Basic {
+				v := orig.B[i]
+				for j, nb := range orig.NonBasic {
+					v += orig.A[i][j] * x[nb]
+				}
+				if v < -eps {
+					t.Errorf("trial %d, %s: row %d violated by %g", trial, h.Name, i, -v)
+				}
+				if math.Abs(v-x[lbl]) > eps {
+					t.Errorf("trial %d, %s: x[%d] = %g, row gives %g", trial, h.Name, lbl, x[lbl], v)
+				}
+			}
+			if obj > best+eps {
+				t.Errorf("trial %d, %s: objective %g exceeds optimum %g", trial, h.Name, obj, best)
+			}
+		}
+	}
+}
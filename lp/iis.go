@@ -0,0 +1,155 @@
+package lp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// IIS is an irreducible infeasible subsystem of a model:
+// a set of constraints and variable bounds which is infeasible
+// but becomes feasible if any one of them is removed.
+type IIS struct {
+	// Indices of the constraints in the subsystem.
+	Cons []int
+	// Indices of the variables whose lower bound is in the subsystem.
+	Lower []int
+	// Indices of the variables whose upper bound is in the subsystem.
+	Upper []int
+}
+
+// FindIIS finds an irreducible infeasible subsystem of an infeasible model
+// using a deletion filter.
+// Each constraint and bound is removed in turn
+// and is restored if the remaining model becomes feasible.
+// The objective and integer restrictions are ignored.
+// Returns an error if the model is feasible.
+func FindIIS(m *Model) (*IIS, error) {
+	return FindIISEps(m, DefaultEps)
+}
+
+func FindIISEps(m *Model, eps float64) (*IIS, error) {
+	// Copy the model without the objective.
+	sub := &Model{
+		Vars: make([]Var, len(m.Vars)),
+		Cons: make([]Con, len(m.Cons)),
+	}
+	for j, v := range m.Vars {
+		sub.Vars[j] = Var{Lower: v.Lower, Upper: v.Upper}
+	}
+	copy(sub.Cons, m.Cons)
+	if feasibleEps(sub, eps) {
+		return nil, errors.New("model is feasible")
+	}
+
+	inf := math.Inf(1)
+	iis := new(IIS)
+	for i := range sub.Cons {
+		con := &sub.Cons[i]
+		if math.IsInf(con.Lower, -1) && math.IsInf(con.Upper, 1) {
+			continue
+		}
+		lower, upper := con.Lower, con.Upper
+		con.Lower, con.Upper = -inf, inf
+		if feasibleEps(sub, eps) {
+			con.Lower, con.Upper = lower, upper
+			iis.Cons = append(iis.Cons, i)
+		}
+	}
+	for j := range sub.Vars {
+		v := &sub.Vars[j]
+		if lower := v.Lower; !math.IsInf(lower, -1) {
+			v.Lower = -inf
+			if feasibleEps(sub, eps) {
+				v.Lower = lower
+				iis.Lower = append(iis.Lower, j)
+			}
+		}
+		if upper := v.Upper; !math.IsInf(upper, 1) {
+			v.Upper = inf
+			if feasibleEps(sub, eps) {
+				v.Upper = upper
+				iis.Upper = append(iis.Upper, j)
+			}
+		}
+	}
+	return iis, nil
+}
+
+// Returns whether the constraints of a model can be satisfied.
+func feasibleEps(m *Model, eps float64) bool {
+	dict := m.Dict()
+	if dict.Feas() {
+		return true
+	}
+	_, infeas := solveFeasEps(dict, eps)
+	return !infeas
+}
+
+// Fprint writes a report of the subsystem
+// with the constraints and bounds of the model.
+// Unnamed constraints and variables are identified by their index.
+func (iis *IIS) Fprint(w io.Writer, m *Model) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "infeasible subsystem of %d constraints and %d bounds\n",
+		len(iis.Cons), len(iis.Lower)+len(iis.Upper))
+	for _, i := range iis.Cons {
+		con := m.Cons[i]
+		name := con.Name
+		if name == "" {
+			name = "constraint " + strconv.Itoa(i)
+		}
+		expr := conExpr(m, con)
+		switch {
+		case con.Lower == con.Upper:
+			fmt.Fprintf(bw, "  %s: %s = %g\n", name, expr, con.Lower)
+		case math.IsInf(con.Lower, -1):
+			fmt.Fprintf(bw, "  %s: %s <= %g\n", name, expr, con.Upper)
+		case math.IsInf(con.Upper, 1):
+			fmt.Fprintf(bw, "  %s: %s >= %g\n", name, expr, con.Lower)
+		default:
+			fmt.Fprintf(bw, "  %s: %g <= %s <= %g\n", name, con.Lower, expr, con.Upper)
+		}
+	}
+	for _, j := range iis.Lower {
+		fmt.Fprintf(bw, "  bound: %s >= %g\n", varName(m, j), m.Vars[j].Lower)
+	}
+	for _, j := range iis.Upper {
+		fmt.Fprintf(bw, "  bound: %s <= %g\n", varName(m, j), m.Vars[j].Upper)
+	}
+	return bw.Flush()
+}
+
+func varName(m *Model, j int) string {
+	if name := m.Vars[j].Name; name != "" {
+		return name
+	}
+	return "x[" + strconv.Itoa(j) + "]"
+}
+
+// Returns the linear expression of a constraint, for example "2 x - y".
+func conExpr(m *Model, con Con) string {
+	var s string
+	for k, j := range con.Vars {
+		a := con.Coeffs[k]
+		switch {
+		case k == 0 && a < 0:
+			s += "-"
+		case k > 0 && a < 0:
+			s += " - "
+		case k > 0:
+			s += " + "
+		}
+		if a = math.Abs(a); a != 1 {
+			s += strconv.FormatFloat(a, 'g', -1, 64) + " "
+		}
+		s += varName(m, j)
+	}
+	if s == "" {
+		s = "0"
+	}
+	return s
+}
@@ -0,0 +1,126 @@
+package lp_test
+
+import (
+	"math"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/jvlmdr/golp/lp"
+)
+
+func ExampleFindIIS() {
+	inf := math.Inf(1)
+	m := lp.NewModel()
+	x := m.AddVar("x", 0, 0, 1)
+	y := m.AddVar("y", 0, 0, inf)
+	z := m.AddVar("z", 0, 0, inf)
+	m.AddCon("sum", 4, inf, []int{x, y}, []float64{1, 1})
+	m.AddCon("diff", math.Inf(-1), 1, []int{x, y}, []float64{-1, 2})
+	m.AddCon("other", 2, 3, []int{y, z}, []float64{1, 1})
+
+	iis, err := lp.FindIIS(m)
+	if err != nil {
+		return
+	}
+	iis.Fprint(os.Stdout, m)
+	// Output:
+	// infeasible subsystem of 2 constraints and 1 bounds
+	//   sum: x + y >= 4
+	//   diff: -x + 2 y <= 1
+	//   bound: x <= 1
+}
+
+// Returns whether the constraints of a model can be satisfied.
+func feasible(m *lp.Model) bool {
+	for j := range m.Vars {
+		m.Vars[j].Obj = 0
+	}
+	_, err := lp.SolveModel(m)
+	return err == nil
+}
+
+func TestFindIIS(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	inf := math.Inf(1)
+	var count int
+	for trial := 0; count < 100; trial++ {
+		n := 1 + r.Intn(4)
+		m := lp.NewModel()
+		for j := 0; j < n; j++ {
+			lo, hi := math.Inf(-1), inf
+			if r.Intn(3) > 0 {
+				lo = float64(r.Intn(5) - 2)
+			}
+			if r.Intn(3) > 0 {
+				hi = float64(r.Intn(5))
+			}
+			m.AddVar("", 0, lo, hi)
+		}
+		for i := r.Intn(6); i > 0; i-- {
+			var con lp.Con
+			for j := 0; j < n; j++ {
+				if r.Intn(2) == 0 {
+					con.Vars = append(con.Vars, j)
+					con.Coeffs = append(con.Coeffs, float64(r.Intn(7)-3))
+				}
+			}
+			lo, hi := float64(r.Intn(11)-5), inf
+			if r.Intn(2) == 0 {
+				lo, hi = math.Inf(-1), lo
+			}
+			m.AddCon("", lo, hi, con.Vars, con.Coeffs)
+		}
+
+		iis, err := lp.FindIIS(m)
+		if feasible(m) {
+			if err == nil {
+				t.Fatalf("trial %d: no error for feasible model", trial)
+			}
+			continue
+		}
+		count++
+		if err != nil {
+			t.Fatalf("trial %d: %v", trial, err)
+		}
+
+		// Returns the subsystem without one of its members.
+		sub := func(skip int) *lp.Model {
+			s := lp.NewModel()
+			for range m.Vars {
+				s.AddVar("", 0, -inf, inf)
+			}
+			var k int
+			for _, i := range iis.Cons {
+				if k != skip {
+					con := m.Cons[i]
+					s.AddCon("", con.Lower, con.Upper, con.Vars, con.Coeffs)
+				}
+				k++
+			}
+			for _, j := range iis.Lower {
+				if k != skip {
+					s.Vars[j].Lower = m.Vars[j].Lower
+				}
+				k++
+			}
+			for _, j := range iis.Upper {
+				if k != skip {
+					s.Vars[j].Upper = m.Vars[j].Upper
+				}
+				k++
+			}
+			return s
+		}
+		if feasible(sub(-1)) {
+			t.Errorf("trial %d: subsystem %+v is feasible", trial, iis)
+			continue
+		}
+		size := len(iis.Cons) + len(iis.Lower) + len(iis.Upper)
+		for k := 0; k < size; k++ {
+			if !feasible(sub(k)) {
+				t.Errorf("trial %d: subsystem %+v without member %d is infeasible", trial, iis, k)
+			}
+		}
+	}
+}
@@ -2,8 +2,8 @@ package lp
 
 import (
 	"fmt"
-	"log"
 	"math"
+	"time"
 )
 
 // SolveInt solves the linear program with the constraint
@@ -13,10 +13,110 @@ func SolveInt(dict *Dict) (final *Dict, err error) {
 }
 
 func SolveIntEps(dict *Dict, eps float64) (final *Dict, err error) {
+	res, err := SolveIntOpts(dict, IntOptions{Eps: eps})
+	if err != nil {
+		return nil, err
+	}
+	if res.Dict == nil {
+		return nil, fmt.Errorf("no integer solution found")
+	}
+	return res.Dict, nil
+}
+
+// IntOptions controls the integer solver.
+// The zero value solves the problem to optimality
+// using cutting planes alone.
+type IntOptions struct {
+	// Tolerance for integrality and pivoting.
+	// If zero, DefaultEps is used.
+	Eps float64
+	// Separators which add cuts alongside the Gomory cutting planes.
+	Separators []Separator
+	// Heuristics which are run on the solution of each relaxation
+	// to find integer solutions before the relaxation becomes integer.
+	Heuristics []Heuristic
+	// Progress is called whenever a better integer solution is found.
+	Progress func(IntResult)
+
+	// Maximum number of rounds of cuts at each node before branching.
+	// If zero, cuts are added until the relaxation is integer
+	// and no branching is performed.
+	// If negative, no cuts are added (pure branch-and-bound).
+	CutRounds int
+	// The search stops when the bound exceeds the incumbent by at most
+	// AbsGap or by at most RelGap times the magnitude of the incumbent.
+	AbsGap float64
+	RelGap float64
+	// Maximum number of nodes to explore.
+	// If zero, there is no limit.
+	NodeLimit int
+	// Maximum duration of the search.
+	// If zero, there is no limit.
+	// Reaching the time limit makes the result depend on timing.
+	TimeLimit time.Duration
+
+	// Number of goroutines which explore nodes concurrently.
+	// If zero, one is used.
+	// Separators, heuristics and the progress function
+	// must be safe to use from multiple goroutines,
+	// although Progress is never called concurrently.
+	Workers int
+	// Explore nodes in synchronized batches of size Workers
+	// so that the result does not depend on the scheduling of goroutines.
+	Deterministic bool
+}
+
+// IntResult is the outcome of the integer solver.
+type IntResult struct {
+	// Dictionary of the relaxation whose solution is the incumbent.
+	// Nil if the incumbent was found by a heuristic.
+	Dict *Dict
+	// Best integer solution found (the incumbent),
+	// in increasing order of label like Soln.
+	// Contains the variables of the original dictionary.
+	// Nil if no integer solution has been found.
+	X []float64
+	// Objective value of X.
+	Obj float64
+	// Upper bound on the objective of any integer solution.
+	Bound float64
+	// Relative gap between the bound and the incumbent,
+	//	(Bound - Obj) / max(|Obj|, 1).
+	// Infinite if there is no incumbent.
+	Gap float64
+	// Number of branch-and-bound nodes explored.
+	Nodes int
+	// Number of rounds of cuts added.
+	CutRounds int
+}
+
+// SolveIntOpts solves the integer program using branch-and-cut.
+// At each node, rounds of Gomory cuts and the cuts found by any separators
+// are added and the relaxation is re-solved using the dual simplex method.
+// Heuristics are run on each relaxation.
+// When no more cuts are permitted, the node is split into two children
+// by bounding a fractional variable.
+//
+// Nodes may be explored concurrently by multiple workers.
+//
+// If a limit is reached before any integer solution is found,
+// the result has no incumbent and the error is nil.
+// Returns an error if the dictionary is not valid (see Validate).
+func SolveIntOpts(dict *Dict, opts IntOptions) (*IntResult, error) {
+	eps := opts.Eps
+	if eps == 0 {
+		eps = DefaultEps
+	}
+	if err := dict.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid dictionary: %v", err)
+	}
+	dict, labels := dict.dense()
+	orig := dict
+
 	if !dict.Feas() {
 		// Solve the feasibility problem.
 		var infeas bool
-		dict, infeas = SolveFeasEps(dict, eps)
+		dict, infeas = solveFeasEps(dict, eps)
 		if infeas {
 			// Continuous relaxation is infeasible,
 			// therefore integer problem is infeasible.
@@ -26,32 +126,20 @@ func SolveIntEps(dict *Dict, eps float64) (final *Dict, err error) {
 
 	// Solve feasible problem without integer constraints.
 	var unbnd bool
-	dict, unbnd = PivotToFinalEps(dict, eps)
+	dict, unbnd = pivotToFinalEps(dict, eps)
 	if unbnd {
 		// Relaxation became unbounded.
 		return nil, fmt.Errorf("unbounded in primal")
 	}
 
-	for !dict.IsIntEps(eps) {
-		log.Println("add cutting-plane constraints")
-		dict = CutPlaneEps(dict, eps)
-		log.Println("feasible?", dict.Feas())
-		log.Println("switch to dual")
-		dict = dict.Dual()
-		log.Println("feasible?", dict.Feas())
-		var unbnd bool
-		dict, unbnd = PivotToFinalEps(dict, eps)
-		if unbnd {
-			// Dual of relaxation became unbounded.
-			log.Println("unbounded in dual, infeasible in primal")
-			return
-		}
-		log.Println("feasible?", dict.Feas())
-		log.Println("switch to primal")
-		dict = dict.Dual()
-		log.Println("objective:", dict.Obj())
+	s := newSearch(orig, labels, opts, eps)
+	s.push(dict)
+	s.run()
+	res := s.result()
+	if res.X == nil && len(s.queue) == 0 {
+		return nil, fmt.Errorf("integer problem is infeasible")
 	}
-	return dict, nil
+	return res, nil
 }
 
 // IsInt returns true if the dictionary is associated with an integer solution.
@@ -80,7 +168,7 @@ func CutPlane(orig *Dict) *Dict {
 func CutPlaneEps(orig *Dict, eps float64) *Dict {
 	var A [][]float64
 	var B []float64
-	m, n := len(orig.Basic), len(orig.NonBasic)
+	n := len(orig.NonBasic)
 
 	for i := range orig.Basic {
 		// Distance from nearest int.
@@ -118,22 +206,5 @@ func CutPlaneEps(orig *Dict, eps float64) *Dict {
 		B = append(B, b)
 	}
 
-	// Copy dictionary.
-	dict := NewDict(m+len(A), n)
-	copy(dict.Basic, orig.Basic)
-	copy(dict.NonBasic, orig.NonBasic)
-	for i := range orig.A {
-		copy(dict.A[i], orig.A[i])
-	}
-	copy(dict.B, orig.B)
-	copy(dict.C, orig.C)
-	dict.D = orig.D
-
-	// Add new rows and slack variables.
-	for i := range A {
-		dict.Basic[m+i] = m + n + i
-		dict.A[m+i] = A[i]
-		dict.B[m+i] = B[i]
-	}
-	return dict
+	return appendRows(orig, A, B)
 }
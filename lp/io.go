@@ -2,132 +2,221 @@ package lp
 
 import (
 	"bufio"
-	"errors"
 	"fmt"
 	"io"
+	"math"
 	"strconv"
 	"strings"
 )
 
-func lineOfInts(line string) ([]int, error) {
-	words := strings.Fields(line)
+// Reads the sections of a file one line at a time.
+// Blank lines and comments are skipped.
+type sectionReader struct {
+	scanner *bufio.Scanner
+	line    int
+	// Line of each value in the last section.
+	lines []int
+}
+
+// Reads count values for the named section.
+// The section begins on a new line and may be wrapped across lines,
+// but must not share its last line with the next section.
+func (r *sectionReader) section(name string, count int) ([]string, error) {
+	var words []string
+	r.lines = r.lines[:0]
+	for len(words) < count {
+		if !r.scanner.Scan() {
+			if err := r.scanner.Err(); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("line %d: %s: want %d values, got %d before end of input",
+				r.line, name, count, len(words))
+		}
+		r.line++
+		line := r.scanner.Text()
+		if k := strings.IndexByte(line, '#'); k >= 0 {
+			line = line[:k]
+		}
+		words = append(words, strings.Fields(line)...)
+		for len(r.lines) < len(words) {
+			r.lines = append(r.lines, r.line)
+		}
+	}
+	if len(words) > count {
+		return nil, fmt.Errorf("line %d: %s: want %d values, got %d", r.line, name, count, len(words))
+	}
+	return words, nil
+}
+
+// Checks that the remaining lines are blank or comments.
+func (r *sectionReader) end() error {
+	for r.scanner.Scan() {
+		r.line++
+		line := r.scanner.Text()
+		if k := strings.IndexByte(line, '#'); k >= 0 {
+			line = line[:k]
+		}
+		if strings.TrimSpace(line) != "" {
+			return fmt.Errorf("line %d: unexpected data after objective", r.line)
+		}
+	}
+	return r.scanner.Err()
+}
+
+func (r *sectionReader) ints(name string, count int) ([]int, error) {
+	words, err := r.section(name, count)
+	if err != nil {
+		return nil, err
+	}
 	nums := make([]int, len(words))
-	for i, str := range words {
-		num, err := strconv.ParseInt(str, 10, 32)
+	for i, word := range words {
+		num, err := strconv.ParseInt(word, 10, 32)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("line %d: %s: invalid integer %q", r.lines[i], name, word)
 		}
 		nums[i] = int(num)
 	}
 	return nums, nil
 }
 
-func lineOfFloats(line string) ([]float64, error) {
-	words := strings.Fields(line)
+func (r *sectionReader) floats(name string, count int) ([]float64, error) {
+	words, err := r.section(name, count)
+	if err != nil {
+		return nil, err
+	}
 	nums := make([]float64, len(words))
-	for i, str := range words {
-		num, err := strconv.ParseFloat(str, 64)
-		if err != nil {
-			return nil, err
+	for i, word := range words {
+		num, err := strconv.ParseFloat(word, 64)
+		if err != nil || math.IsNaN(num) || math.IsInf(num, 0) {
+			return nil, fmt.Errorf("line %d: %s: invalid number %q", r.lines[i], name, word)
 		}
 		nums[i] = num
 	}
 	return nums, nil
 }
 
-func readLine(scanner *bufio.Scanner) error {
-	if !scanner.Scan() {
-		err := scanner.Err()
-		if err == nil {
-			return io.EOF
-		}
-		return err
-	}
-	return nil
-}
-
 // ReadDictColoradoFrom reads a dictionary in the University of Colorado format.
+//
+// The file contains the dimensions m and n, the m basic labels,
+// the n non-basic labels, the m constants b, the m rows of A
+// and finally the objective constant followed by the n coefficients c.
+// Each section begins on a new line and may be wrapped across several lines.
+// Blank lines are ignored and a # starts a comment
+// which extends to the end of the line.
+// The labels must be a permutation of 1, ..., m+n.
+// Errors give the line at which they occurred.
 func ReadDictColoradoFrom(r io.Reader) (*Dict, error) {
 	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, math.MaxInt32)
+	sr := &sectionReader{scanner: scanner}
 
-	// First line contains dimensions.
-	if err := readLine(scanner); err != nil {
-		return nil, err
-	}
-	dims, err := lineOfInts(scanner.Text())
+	// First section contains dimensions.
+	dims, err := sr.ints("dimensions", 2)
 	if err != nil {
 		return nil, err
 	}
-	if len(dims) != 2 {
-		return nil, errors.New("wrong number of dimensions")
-	}
 	m, n := dims[0], dims[1]
+	if m < 0 || n < 0 {
+		return nil, fmt.Errorf("line %d: dimensions: negative size %d x %d", sr.line, m, n)
+	}
 
-	// Second line contains basic indices.
-	if err := readLine(scanner); err != nil {
+	basic, err := sr.ints("basic labels", m)
+	if err != nil {
 		return nil, err
 	}
-	basic, err := lineOfInts(scanner.Text())
+	nonbasic, err := sr.ints("non-basic labels", n)
 	if err != nil {
 		return nil, err
 	}
-	if len(basic) != m {
-		return nil, errors.New("wrong number of basic vars")
+	if err := checkColoradoLabels(basic, nonbasic); err != nil {
+		return nil, fmt.Errorf("line %d: %v", sr.line, err)
 	}
 
-	// Third line contains non-basic indices.
-	if err := readLine(scanner); err != nil {
-		return nil, err
-	}
-	nonbasic, err := lineOfInts(scanner.Text())
+	b, err := sr.floats("constants", m)
 	if err != nil {
 		return nil, err
 	}
-	if len(nonbasic) != n {
-		return nil, errors.New("wrong number of non-basic vars")
+	a := make([][]float64, m)
+	for i := range a {
+		a[i], err = sr.floats(fmt.Sprintf("row %d of coefficients", i+1), n)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// Fourth line contains b.
-	if err := readLine(scanner); err != nil {
-		return nil, err
-	}
-	b, err := lineOfFloats(scanner.Text())
+	// Last section is objective constant and coefficients.
+	obj, err := sr.floats("objective", n+1)
 	if err != nil {
 		return nil, err
 	}
-	if len(b) != m {
-		return nil, errors.New("constraint constants are wrong length")
+	d, c := obj[0], obj[1:]
+
+	if err := sr.end(); err != nil {
+		return nil, err
 	}
+	return &Dict{basic, nonbasic, a, b, c, d}, nil
+}
 
-	// Following m lines contain coefficients.
-	a := make([][]float64, m)
-	for i := 0; i < m; i++ {
-		if err := readLine(scanner); err != nil {
-			return nil, err
+// Returns an error if the labels are not a permutation of 1, ..., m+n.
+func checkColoradoLabels(basic, nonbasic []int) error {
+	p := len(basic) + len(nonbasic)
+	seen := make([]bool, p+1)
+	for _, lbl := range append(append([]int(nil), basic...), nonbasic...) {
+		if lbl < 1 || lbl > p {
+			return fmt.Errorf("label %d is not in 1..%d", lbl, p)
 		}
-		coeff, err := lineOfFloats(scanner.Text())
-		if err != nil {
-			return nil, err
+		if seen[lbl] {
+			return fmt.Errorf("label %d is repeated", lbl)
 		}
-		if len(coeff) != n {
-			return nil, errors.New("constraint coefficients are wrong length")
-		}
-		a[i] = coeff
+		seen[lbl] = true
 	}
+	return nil
+}
 
-	// Last line is objective constant and coefficients.
-	if err := readLine(scanner); err != nil {
-		return nil, err
-	}
-	obj, err := lineOfFloats(scanner.Text())
-	if err != nil {
-		return nil, err
+// WriteDictColoradoTo writes a dictionary in the University of Colorado format.
+// Numbers are written with the fewest digits which are read back exactly,
+// so that ReadDictColoradoFrom returns an identical dictionary.
+// Returns an error if the labels are not a permutation of 1, ..., m+n,
+// as required by the format.
+func WriteDictColoradoTo(w io.Writer, dict *Dict) error {
+	return WriteDictColoradoPrecTo(w, dict, -1)
+}
+
+// WriteDictColoradoPrecTo writes a dictionary in the University of Colorado format
+// with numbers rounded to prec significant digits.
+// If prec is negative, numbers are written exactly.
+// The labels must be a permutation of 1, ..., m+n.
+func WriteDictColoradoPrecTo(w io.Writer, dict *Dict, prec int) error {
+	if err := checkColoradoLabels(dict.Basic, dict.NonBasic); err != nil {
+		return err
 	}
-	if len(obj) != n+1 {
-		msg := fmt.Sprint("objective coefficients are wrong length: ", len(obj))
-		return nil, errors.New(msg)
+	bw := bufio.NewWriter(w)
+	ints := func(xs []int) {
+		for i, x := range xs {
+			if i > 0 {
+				bw.WriteString(" ")
+			}
+			bw.WriteString(strconv.Itoa(x))
+		}
+		bw.WriteString("\n")
+	}
+	floats := func(xs ...float64) {
+		for i, x := range xs {
+			if i > 0 {
+				bw.WriteString(" ")
+			}
+			bw.WriteString(strconv.FormatFloat(x, 'g', prec, 64))
+		}
+		bw.WriteString("\n")
 	}
-	d, c := obj[0], obj[1:]
 
-	return &Dict{basic, nonbasic, a, b, c, d}, nil
+	ints([]int{len(dict.Basic), len(dict.NonBasic)})
+	ints(dict.Basic)
+	ints(dict.NonBasic)
+	floats(dict.B...)
+	for _, ai := range dict.A {
+		floats(ai...)
+	}
+	floats(append([]float64{dict.D}, dict.C...)...)
+	return bw.Flush()
 }
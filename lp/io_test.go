@@ -0,0 +1,145 @@
+package lp_test
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jvlmdr/golp/lp"
+)
+
+func ExampleWriteDictColoradoPrecTo() {
+	dict := lp.NewDict(2, 2)
+	dict.Basic = []int{3, 4}
+	dict.NonBasic = []int{1, 2}
+	dict.A = [][]float64{{-1, 1}, {-2, -1.0 / 3}}
+	dict.B = []float64{1, 4}
+	dict.C = []float64{1, 2.0 / 3}
+	dict.D = 0.5
+	lp.WriteDictColoradoPrecTo(os.Stdout, dict, 4)
+	// Output:
+	// 2 2
+	// 3 4
+	// 1 2
+	// 1 4
+	// -1 1
+	// -2 -0.3333
+	// 0.5 1 0.6667
+}
+
+// Returns a random dictionary with labels 1, ..., m+n in random order
+// as in the University of Colorado files.
+func randColoradoDict(r *rand.Rand, m, n int) *lp.Dict {
+	dict := lp.NewDict(m, n)
+	perm := r.Perm(m + n)
+	for j := range dict.NonBasic {
+		dict.NonBasic[j] = perm[j] + 1
+		dict.C[j] = r.NormFloat64()
+	}
+	for i := range dict.Basic {
+		dict.Basic[i] = perm[n+i] + 1
+		for j := range dict.NonBasic {
+			dict.A[i][j] = r.NormFloat64() * 1e3
+		}
+		dict.B[i] = r.Float64() - 0.25
+	}
+	dict.D = r.NormFloat64()
+	return dict
+}
+
+func TestWriteDictColoradoTo(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 100; trial++ {
+		dict := randColoradoDict(r, 1+r.Intn(5), 1+r.Intn(5))
+		dicts := []*lp.Dict{dict}
+		if !dict.Feas() {
+			// Intermediate dictionaries can also be saved.
+			dicts = append(dicts, lp.ToFeasDict(dict))
+		}
+		for _, want := range dicts {
+			var b bytes.Buffer
+			if err := lp.WriteDictColoradoTo(&b, want); err != nil {
+				t.Fatal(err)
+			}
+			text := b.String()
+			got, err := lp.ReadDictColoradoFrom(&b)
+			if err != nil {
+				t.Fatalf("%v:\n%s", err, text)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("round trip differs:\n%s\ngot  %+v\nwant %+v", text, got, want)
+			}
+		}
+	}
+}
+
+func TestWriteDictColoradoTo_labels(t *testing.T) {
+	// Labelled from zero.
+	dict := lp.NewDict(2, 2)
+	dict.Basic = []int{2, 3}
+	dict.NonBasic = []int{0, 1}
+	var b bytes.Buffer
+	err := lp.WriteDictColoradoTo(&b, dict)
+	if want := "label 0 is not in 1..4"; err == nil || err.Error() != want {
+		t.Fatalf("want error %q, got %v", want, err)
+	}
+	if b.Len() != 0 {
+		t.Fatalf("wrote %q", b.String())
+	}
+}
+
+func TestReadDictColoradoFrom(t *testing.T) {
+	const in = `# Example with wrapped values.
+2 3
+
+1 3
+2 4
+  5
+1 -2
+-1 0.5  # comment
+   2
+3 -1 4
+0 1 1 1
+`
+	want := &lp.Dict{
+		Basic:    []int{1, 3},
+		NonBasic: []int{2, 4, 5},
+		A:        [][]float64{{-1, 0.5, 2}, {3, -1, 4}},
+		B:        []float64{1, -2},
+		C:        []float64{1, 1, 1},
+	}
+	got, err := lp.ReadDictColoradoFrom(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// Long lines are accepted.
+	var b bytes.Buffer
+	if err := lp.WriteDictColoradoTo(&b, randColoradoDict(rand.New(rand.NewSource(1)), 2, 20000)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lp.ReadDictColoradoFrom(&b); err != nil {
+		t.Error(err)
+	}
+
+	cases := []struct{ in, err string }{
+		{strings.Replace(in, "1 3\n", "1 3 6\n", 1), "line 4: basic labels: want 2 values, got 3"},
+		{strings.Replace(in, "1 3\n", "1 2\n", 1), "line 6: label 2 is repeated"},
+		{strings.Replace(in, "1 3\n", "1 6\n", 1), "line 6: label 6 is not in 1..5"},
+		{strings.Replace(in, "0.5", "x", 1), "line 8: row 1 of coefficients: invalid number \"x\""},
+		{strings.Replace(in, "0 1 1 1\n", "0 1 1\n", 1), "line 11: objective: want 4 values, got 3 before end of input"},
+		{in + "7\n", "line 12: unexpected data after objective"},
+	}
+	for _, c := range cases {
+		_, err := lp.ReadDictColoradoFrom(strings.NewReader(c.in))
+		if err == nil || err.Error() != c.err {
+			t.Errorf("want error %q, got %v", c.err, err)
+		}
+	}
+}
@@ -0,0 +1,757 @@
+package lp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ReadLPFrom reads a model in CPLEX LP format.
+//
+// The sections Maximize (or Minimize), Subject To, Bounds, General, Binary
+// and End are supported. Keywords are not case sensitive and
+// must begin a line. A backslash starts a comment which extends
+// to the end of the line, and a comment of the form
+//	\Problem name: NAME
+// gives the name of the model.
+// Constraints may be ranges of the form
+//	name: lower <= expr <= upper
+// and variables have bounds [0, inf) unless given in the Bounds section.
+// Errors give the line and column at which they occurred.
+//
+// Use the Dict method of the model to obtain a dictionary which can be solved.
+func ReadLPFrom(r io.Reader) (*Model, error) {
+	toks, name, err := lpTokens(r)
+	if err != nil {
+		return nil, err
+	}
+	p := &lpParser{
+		toks: toks,
+		m:    NewModel(),
+		vars: make(map[string]int),
+		cons: make(map[string]bool),
+	}
+	p.m.Name = name
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p.m, nil
+}
+
+// Kinds of tokens in LP format.
+const (
+	lpEOF = iota
+	lpName
+	lpNum
+	lpOp
+	lpSign
+	lpColon
+	lpSection
+)
+
+type lpToken struct {
+	kind int
+	// Text of the token.
+	// The text of operators and sections is canonical.
+	text      string
+	num       float64
+	line, col int
+}
+
+func (t lpToken) String() string {
+	if t.kind == lpEOF {
+		return "end of file"
+	}
+	return strconv.Quote(t.text)
+}
+
+// Canonical names of section keywords.
+var lpSections = map[string]string{
+	"maximize": "max", "maximise": "max", "maximum": "max", "max": "max",
+	"minimize": "min", "minimise": "min", "minimum": "min", "min": "min",
+	"st": "st", "s.t.": "st", "st.": "st",
+	"bounds": "bounds", "bound": "bounds",
+	"general": "general", "generals": "general", "gen": "general",
+	"integer": "general", "integers": "general",
+	"binary": "binary", "binaries": "binary", "bin": "binary",
+	"end": "end",
+}
+
+// Keywords of two words.
+var lpSections2 = map[string]string{"subject to": "st", "such that": "st"}
+
+// Characters other than letters and digits which may appear in names.
+const lpNameChars = "!\"#$%&()/,.;?@_`'{}|~"
+
+func isLPNameChar(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || isDigit(c) ||
+		strings.IndexByte(lpNameChars, c) >= 0
+}
+
+func isDigit(c byte) bool { return '0' <= c && c <= '9' }
+
+// Splits the input into tokens and returns the problem name if one is given.
+func lpTokens(r io.Reader) (toks []lpToken, name string, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, math.MaxInt32)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := scanner.Text()
+		if k := strings.IndexByte(line, '\\'); k >= 0 {
+			comment := strings.TrimSpace(line[k+1:])
+			if strings.HasPrefix(comment, "Problem name:") {
+				name = strings.TrimSpace(comment[len("Problem name:"):])
+			}
+			line = line[:k]
+		}
+		lineToks, err := lpLineTokens(line, lineNum)
+		if err != nil {
+			return nil, "", err
+		}
+		toks = append(toks, lpKeyword(lineToks)...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, "", err
+	}
+	return toks, name, nil
+}
+
+// Replaces a keyword at the start of a line with a section token.
+func lpKeyword(toks []lpToken) []lpToken {
+	if len(toks) == 0 || toks[0].kind != lpName {
+		return toks
+	}
+	t := toks[0]
+	if len(toks) > 1 && toks[1].kind == lpName {
+		key := strings.ToLower(t.text + " " + toks[1].text)
+		if s, ok := lpSections2[key]; ok {
+			t.kind, t.text = lpSection, s
+			return append([]lpToken{t}, toks[2:]...)
+		}
+	}
+	if s, ok := lpSections[strings.ToLower(t.text)]; ok {
+		t.kind, t.text = lpSection, s
+		toks[0] = t
+	}
+	return toks
+}
+
+func lpLineTokens(line string, lineNum int) ([]lpToken, error) {
+	var toks []lpToken
+	for i := 0; i < len(line); {
+		c := line[i]
+		if c == ' ' || c == '\t' || c == '\r' {
+			i++
+			continue
+		}
+		t := lpToken{line: lineNum, col: i + 1}
+		start := i
+		switch {
+		case isDigit(c) || c == '.' && i+1 < len(line) && isDigit(line[i+1]):
+			for i < len(line) && (isDigit(line[i]) || line[i] == '.') {
+				i++
+			}
+			// Exponent must contain at least one digit.
+			if i < len(line) && (line[i] == 'e' || line[i] == 'E') {
+				k := i + 1
+				if k < len(line) && (line[k] == '+' || line[k] == '-') {
+					k++
+				}
+				if k < len(line) && isDigit(line[k]) {
+					i = k
+					for i < len(line) && isDigit(line[i]) {
+						i++
+					}
+				}
+			}
+			t.kind, t.text = lpNum, line[start:i]
+			num, err := strconv.ParseFloat(t.text, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d, column %d: invalid number %q", t.line, t.col, t.text)
+			}
+			t.num = num
+		case isLPNameChar(c) && c != '.':
+			for i < len(line) && isLPNameChar(line[i]) {
+				i++
+			}
+			t.kind, t.text = lpName, line[start:i]
+		case c == '<' || c == '>' || c == '=':
+			i++
+			if i < len(line) && (line[i] == '=' || c == '=' && (line[i] == '<' || line[i] == '>')) {
+				i++
+			}
+			t.kind = lpOp
+			switch {
+			case strings.ContainsRune(line[start:i], '<'):
+				t.text = "<="
+			case strings.ContainsRune(line[start:i], '>'):
+				t.text = ">="
+			default:
+				t.text = "="
+			}
+		case c == '+' || c == '-':
+			i++
+			t.kind, t.text = lpSign, line[start:i]
+		case c == ':':
+			i++
+			t.kind, t.text = lpColon, ":"
+		default:
+			return nil, fmt.Errorf("line %d, column %d: unexpected character %q", t.line, t.col, c)
+		}
+		toks = append(toks, t)
+	}
+	return toks, nil
+}
+
+// State of the LP format parser.
+type lpParser struct {
+	toks []lpToken
+	pos  int
+	m    *Model
+	// Index of each variable by name.
+	vars map[string]int
+	// Names of constraints which have been seen.
+	cons   map[string]bool
+	hasObj bool
+}
+
+func (p *lpParser) peekAt(k int) lpToken {
+	if p.pos+k < len(p.toks) {
+		return p.toks[p.pos+k]
+	}
+	t := lpToken{kind: lpEOF}
+	if n := len(p.toks); n > 0 {
+		t.line, t.col = p.toks[n-1].line, p.toks[n-1].col
+	}
+	return t
+}
+
+func (p *lpParser) peek() lpToken { return p.peekAt(0) }
+
+func (p *lpParser) next() lpToken {
+	t := p.peek()
+	if p.pos < len(p.toks) {
+		p.pos++
+	}
+	return t
+}
+
+func (p *lpParser) errorf(t lpToken, format string, args ...interface{}) error {
+	return fmt.Errorf("line %d, column %d: %s", t.line, t.col, fmt.Sprintf(format, args...))
+}
+
+// Returns true if the next token ends the current section.
+func (p *lpParser) atSection() bool {
+	k := p.peek().kind
+	return k == lpSection || k == lpEOF
+}
+
+func (p *lpParser) parse() error {
+	for {
+		t := p.next()
+		if t.kind == lpEOF {
+			return errors.New("missing End")
+		}
+		if t.kind != lpSection {
+			return p.errorf(t, "unexpected %v outside of section", t)
+		}
+		var err error
+		switch t.text {
+		case "max", "min":
+			if p.hasObj {
+				return p.errorf(t, "duplicate objective")
+			}
+			p.hasObj = true
+			p.m.Min = t.text == "min"
+			err = p.objective()
+		case "st":
+			for err == nil && !p.atSection() {
+				err = p.constraint()
+			}
+		case "bounds":
+			for err == nil && !p.atSection() {
+				err = p.bound()
+			}
+		case "general", "binary":
+			for err == nil && !p.atSection() {
+				err = p.integer(t.text == "binary")
+			}
+		case "end":
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Returns the index of a variable, adding it if necessary.
+func (p *lpParser) variable(name string) int {
+	j, ok := p.vars[name]
+	if !ok {
+		j = p.m.AddVar(name, 0, 0, math.Inf(1))
+		p.vars[name] = j
+	}
+	return j
+}
+
+// Reads the name of an objective or constraint if present.
+func (p *lpParser) label() string {
+	if p.peek().kind == lpName && p.peekAt(1).kind == lpColon {
+		name := p.next().text
+		p.next()
+		return name
+	}
+	return ""
+}
+
+func isLPInf(t lpToken) bool {
+	if t.kind != lpName {
+		return false
+	}
+	s := strings.ToLower(t.text)
+	return s == "inf" || s == "infinity"
+}
+
+// Returns true if the next tokens are a value followed by an operator.
+func (p *lpParser) atValueOp() bool {
+	k := 0
+	if p.peek().kind == lpSign {
+		k++
+	}
+	t := p.peekAt(k)
+	return (t.kind == lpNum || isLPInf(t)) && p.peekAt(k+1).kind == lpOp
+}
+
+// Reads a signed number, which may be infinite.
+func (p *lpParser) value() (float64, error) {
+	sign := 1.0
+	if p.peek().kind == lpSign {
+		if p.next().text == "-" {
+			sign = -1
+		}
+	}
+	t := p.next()
+	switch {
+	case t.kind == lpNum:
+		return sign * t.num, nil
+	case isLPInf(t):
+		return sign * math.Inf(1), nil
+	}
+	return 0, p.errorf(t, "expected number, got %v", t)
+}
+
+func (p *lpParser) op() (string, error) {
+	t := p.next()
+	if t.kind != lpOp {
+		return "", p.errorf(t, "expected operator, got %v", t)
+	}
+	return t.text, nil
+}
+
+// Reads a linear expression.
+// Repeated variables are combined.
+func (p *lpParser) expr() (vars []int, coeffs []float64, c float64, err error) {
+	index := make(map[int]int)
+	for first := true; ; first = false {
+		t := p.peek()
+		sign, hasSign := 1.0, t.kind == lpSign
+		if hasSign {
+			p.next()
+			if t.text == "-" {
+				sign = -1
+			}
+		} else if !first {
+			return vars, coeffs, c, nil
+		}
+		t = p.peek()
+		coef, hasNum := sign, false
+		if t.kind == lpNum {
+			p.next()
+			coef *= t.num
+			hasNum = true
+			t = p.peek()
+		}
+		switch {
+		case t.kind == lpName:
+			p.next()
+			j := p.variable(t.text)
+			k, ok := index[j]
+			if !ok {
+				k = len(vars)
+				index[j] = k
+				vars = append(vars, j)
+				coeffs = append(coeffs, 0)
+			}
+			coeffs[k] += coef
+		case hasNum:
+			c += coef
+		case first && !hasSign:
+			// Empty expression.
+			return nil, nil, 0, nil
+		default:
+			return nil, nil, 0, p.errorf(t, "expected term, got %v", t)
+		}
+	}
+}
+
+func (p *lpParser) objective() error {
+	p.label()
+	vars, coeffs, c, err := p.expr()
+	if err != nil {
+		return err
+	}
+	if !p.atSection() {
+		t := p.peek()
+		return p.errorf(t, "unexpected %v in objective", t)
+	}
+	for k, j := range vars {
+		p.m.Vars[j].Obj += coeffs[k]
+	}
+	p.m.Const += c
+	return nil
+}
+
+func (p *lpParser) constraint() error {
+	start := p.peek()
+	name := p.label()
+	if name != "" {
+		if p.cons[name] {
+			return p.errorf(start, "duplicate constraint %q", name)
+		}
+		p.cons[name] = true
+	}
+	con := Con{Name: name}
+	if p.atValueOp() {
+		// Range constraint.
+		lo, err := p.value()
+		if err != nil {
+			return err
+		}
+		optok := p.peek()
+		op, _ := p.op()
+		var c float64
+		con.Vars, con.Coeffs, c, err = p.expr()
+		if err != nil {
+			return err
+		}
+		op2, err := p.op()
+		if err != nil {
+			return err
+		}
+		hi, err := p.value()
+		if err != nil {
+			return err
+		}
+		if op != op2 || op == "=" {
+			return p.errorf(optok, "range must use matching inequalities")
+		}
+		if op == ">=" {
+			lo, hi = hi, lo
+		}
+		con.Lower, con.Upper = lo-c, hi-c
+	} else {
+		var (
+			c   float64
+			err error
+		)
+		con.Vars, con.Coeffs, c, err = p.expr()
+		if err != nil {
+			return err
+		}
+		op, err := p.op()
+		if err != nil {
+			return err
+		}
+		b, err := p.value()
+		if err != nil {
+			return err
+		}
+		b -= c
+		switch op {
+		case "<=":
+			con.Lower, con.Upper = math.Inf(-1), b
+		case ">=":
+			con.Lower, con.Upper = b, math.Inf(1)
+		default:
+			con.Lower, con.Upper = b, b
+		}
+	}
+	p.m.Cons = append(p.m.Cons, con)
+	return nil
+}
+
+// Applies the bound
+//	val op x    if left is true, or
+//	x op val    otherwise.
+func (p *lpParser) applyBound(j int, op string, val float64, left bool) {
+	v := &p.m.Vars[j]
+	if left {
+		switch op {
+		case "<=":
+			op = ">="
+		case ">=":
+			op = "<="
+		}
+	}
+	switch op {
+	case "<=":
+		v.Upper = val
+	case ">=":
+		v.Lower = val
+	default:
+		v.Lower, v.Upper = val, val
+	}
+}
+
+func (p *lpParser) bound() error {
+	t := p.peek()
+	if t.kind == lpName && !isLPInf(t) {
+		p.next()
+		j := p.variable(t.text)
+		if u := p.peek(); u.kind == lpName && strings.ToLower(u.text) == "free" {
+			p.next()
+			p.m.Vars[j].Lower, p.m.Vars[j].Upper = math.Inf(-1), math.Inf(1)
+			return nil
+		}
+		op, err := p.op()
+		if err != nil {
+			return err
+		}
+		val, err := p.value()
+		if err != nil {
+			return err
+		}
+		p.applyBound(j, op, val, false)
+		return nil
+	}
+
+	val, err := p.value()
+	if err != nil {
+		return err
+	}
+	op, err := p.op()
+	if err != nil {
+		return err
+	}
+	t = p.next()
+	if t.kind != lpName || isLPInf(t) {
+		return p.errorf(t, "expected variable, got %v", t)
+	}
+	j := p.variable(t.text)
+	p.applyBound(j, op, val, true)
+	if p.peek().kind != lpOp {
+		return nil
+	}
+	op, _ = p.op()
+	val, err = p.value()
+	if err != nil {
+		return err
+	}
+	p.applyBound(j, op, val, false)
+	return nil
+}
+
+func (p *lpParser) integer(binary bool) error {
+	t := p.next()
+	if t.kind != lpName {
+		return p.errorf(t, "expected variable, got %v", t)
+	}
+	v := &p.m.Vars[p.variable(t.text)]
+	v.Int = true
+	if binary {
+		v.Lower, v.Upper = 0, 1
+	}
+	return nil
+}
+
+// WriteLPTo writes a model in CPLEX LP format.
+// Names must be valid in LP format and must not be keywords.
+// Variables and constraints without names are given names.
+//
+// Every variable appears in the objective, with a zero coefficient if necessary,
+// so that the order of the variables is preserved.
+func WriteLPTo(w io.Writer, m *Model) error {
+	rows, cols, obj, err := mpsNames(m, true)
+	if err != nil {
+		return err
+	}
+	for _, names := range [][]string{rows, cols} {
+		for _, name := range names {
+			if err := checkLPName(name); err != nil {
+				return err
+			}
+		}
+	}
+	lw := &lpWriter{w: bufio.NewWriter(w)}
+
+	if m.Name != "" {
+		fmt.Fprintf(lw.w, "\\Problem name: %s\n\n", m.Name)
+	}
+	if m.Min {
+		lw.w.WriteString("Minimize\n")
+	} else {
+		lw.w.WriteString("Maximize\n")
+	}
+	lw.start(" " + obj + ":")
+	for j, v := range m.Vars {
+		lw.term(v.Obj, cols[j])
+	}
+	if m.Const != 0 {
+		lw.term(m.Const, "")
+	}
+	lw.end()
+
+	lw.w.WriteString("Subject To\n")
+	inf := math.Inf(1)
+	for i, con := range m.Cons {
+		lw.start(" " + rows[i] + ":")
+		ranged := !math.IsInf(con.Lower, -1) && !math.IsInf(con.Upper, 1) && con.Lower != con.Upper
+		if ranged {
+			lw.add(" " + formatLPNum(con.Lower) + " <=")
+		}
+		for k, j := range con.Vars {
+			lw.term(con.Coeffs[k], cols[j])
+		}
+		switch {
+		case ranged:
+			lw.add(" <= " + formatLPNum(con.Upper))
+		case con.Lower == con.Upper:
+			lw.add(" = " + formatLPNum(con.Lower))
+		case !math.IsInf(con.Upper, 1):
+			lw.add(" <= " + formatLPNum(con.Upper))
+		default:
+			// Lower bound may be -inf.
+			lw.add(" >= " + formatLPNum(con.Lower))
+		}
+		lw.end()
+	}
+
+	var bounds, general, binary []string
+	for j, v := range m.Vars {
+		name := cols[j]
+		if v.Int {
+			if v.Lower == 0 && v.Upper == 1 {
+				binary = append(binary, name)
+				continue
+			}
+			general = append(general, name)
+		}
+		switch {
+		case v.Lower == v.Upper:
+			bounds = append(bounds, name+" = "+formatLPNum(v.Lower))
+		case math.IsInf(v.Lower, -1) && v.Upper == inf:
+			bounds = append(bounds, name+" free")
+		case v.Upper == inf:
+			if v.Lower != 0 {
+				bounds = append(bounds, name+" >= "+formatLPNum(v.Lower))
+			}
+		default:
+			bounds = append(bounds, formatLPNum(v.Lower)+" <= "+name+" <= "+formatLPNum(v.Upper))
+		}
+	}
+	if len(bounds) > 0 {
+		lw.w.WriteString("Bounds\n")
+		for _, b := range bounds {
+			lw.w.WriteString(" " + b + "\n")
+		}
+	}
+	for _, sec := range []struct {
+		head  string
+		names []string
+	}{{"General", general}, {"Binary", binary}} {
+		if len(sec.names) == 0 {
+			continue
+		}
+		lw.w.WriteString(sec.head + "\n")
+		lw.start("")
+		for _, name := range sec.names {
+			lw.add(" " + name)
+		}
+		lw.end()
+	}
+	lw.w.WriteString("End\n")
+	return lw.w.Flush()
+}
+
+// Maximum length of a line written in LP format.
+// Longer expressions are continued on the next line.
+const lpLineLen = 78
+
+// Writes lines of an LP file.
+type lpWriter struct {
+	w    *bufio.Writer
+	line []byte
+	// Whether no term has been written in the current expression.
+	first bool
+}
+
+func (lw *lpWriter) start(s string) {
+	lw.line = append(lw.line[:0], s...)
+	lw.first = true
+}
+
+// Appends to the current line, starting a new line if it is too long.
+func (lw *lpWriter) add(s string) {
+	if len(lw.line)+len(s) > lpLineLen && len(strings.TrimSpace(string(lw.line))) > 0 {
+		lw.end()
+		lw.line = append(lw.line, "  "...)
+	}
+	lw.line = append(lw.line, s...)
+}
+
+// Appends a term of an expression, or a constant if the name is empty.
+func (lw *lpWriter) term(a float64, name string) {
+	sign := " +"
+	if a < 0 || a == 0 && math.Signbit(a) {
+		sign, a = " -", -a
+	}
+	if lw.first && sign == " +" {
+		sign = ""
+	}
+	lw.first = false
+	s := sign + " " + formatLPNum(a)
+	if name != "" {
+		if a == 1 {
+			s = sign
+		}
+		s += " " + name
+	}
+	lw.add(s)
+}
+
+func (lw *lpWriter) end() {
+	lw.line = append(lw.line, '\n')
+	lw.w.Write(lw.line)
+	lw.line = lw.line[:0]
+}
+
+func formatLPNum(x float64) string {
+	switch {
+	case math.IsInf(x, 1):
+		return "inf"
+	case math.IsInf(x, -1):
+		return "-inf"
+	}
+	return strconv.FormatFloat(x, 'g', -1, 64)
+}
+
+// Returns an error if the name can not be read as a single name.
+func checkLPName(name string) error {
+	valid := name != "" && name[0] != '.' && !isDigit(name[0])
+	for i := 0; valid && i < len(name); i++ {
+		valid = isLPNameChar(name[i])
+	}
+	if !valid {
+		return fmt.Errorf("invalid name %q", name)
+	}
+	s := strings.ToLower(name)
+	if _, ok := lpSections[s]; ok || s == "inf" || s == "infinity" || s == "free" ||
+		s == "subject" || s == "such" {
+		return fmt.Errorf("name %q is a keyword", name)
+	}
+	return nil
+}
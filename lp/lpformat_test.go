@@ -0,0 +1,91 @@
+package lp_test
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jvlmdr/golp/lp"
+)
+
+const testLP = `\Problem name: example
+Maximize
+ obj: 3 x + 2y - z + 1
+Subject To
+ c1: x + y + z <= 10
+ c2: x - y >= -2
+ c3: -5 <= x - 2 z
+       <= 8
+Bounds
+ x <= 4
+ -1 <= z <= 1
+General
+ y
+End
+`
+
+func ExampleReadLPFrom() {
+	m, err := lp.ReadLPFrom(strings.NewReader(testLP))
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+	for _, v := range m.Vars {
+		fmt.Printf("%s in [%g, %g], obj %g, int %v\n", v.Name, v.Lower, v.Upper, v.Obj, v.Int)
+	}
+	for _, con := range m.Cons {
+		fmt.Printf("%s in [%g, %g]\n", con.Name, con.Lower, con.Upper)
+	}
+	s, err := lp.SolveModel(m)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+	fmt.Printf("%.6g at %.6g\n", s.Obj, s.X)
+	// Output:
+	// x in [0, 4], obj 3, int false
+	// y in [0, +Inf], obj 2, int true
+	// z in [-1, 1], obj -1, int false
+	// c1 in [-Inf, 10]
+	// c2 in [-2, +Inf]
+	// c3 in [-5, 8]
+	// 26 at [4 6 -1]
+}
+
+func TestReadLPFrom_error(t *testing.T) {
+	cases := []struct{ in, err string }{
+		{strings.Replace(testLP, "x - y >=", "x - y >= >=", 1), "line 6, column 15: expected number"},
+		{strings.Replace(testLP, "2y", "2y *", 1), "line 3, column 16: unexpected character"},
+		{strings.Replace(testLP, "End\n", "", 1), "missing End"},
+		{strings.Replace(testLP, "c2:", "c1:", 1), "line 6, column 2: duplicate constraint"},
+		{"x + y\n" + testLP, "line 1, column 1: unexpected \"x\" outside of section"},
+	}
+	for _, c := range cases {
+		_, err := lp.ReadLPFrom(strings.NewReader(c.in))
+		if err == nil || !strings.HasPrefix(err.Error(), c.err) {
+			t.Errorf("want error %q, got %v", c.err, err)
+		}
+	}
+}
+
+func TestWriteLPTo(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 100; trial++ {
+		m := randFormatModel(r)
+		var b bytes.Buffer
+		if err := lp.WriteLPTo(&b, m); err != nil {
+			t.Fatal(err)
+		}
+		text := b.String()
+		got, err := lp.ReadLPFrom(&b)
+		if err != nil {
+			t.Fatalf("%v:\n%s", err, text)
+		}
+		if !reflect.DeepEqual(canonModel(got), canonModel(m)) {
+			t.Fatalf("round trip differs:\n%s\ngot  %+v\nwant %+v", text, canonModel(got), canonModel(m))
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package lp
+
+import "math"
+
+// Model describes a linear program in terms of named variables and constraints.
+//	max  Const + sum_j Vars[j].Obj x[j]
+//	s.t. Cons[i].Lower <= sum_k Cons[i].Coeffs[k] x[Cons[i].Vars[k]] <= Cons[i].Upper
+//	     Vars[j].Lower <= x[j] <= Vars[j].Upper
+// If Min is true, the objective is minimized instead.
+// Infinite bounds are represented by math.Inf.
+//
+// Unlike a dictionary, variables may be free or bounded above
+// and constraints may be equalities or ranges.
+// Use Dict to obtain a dictionary which can be solved.
+type Model struct {
+	Name  string
+	Vars  []Var
+	Cons  []Con
+	Const float64
+	Min   bool
+}
+
+// Var is a variable of a model.
+type Var struct {
+	Name  string
+	Obj   float64
+	Lower float64
+	Upper float64
+	Int   bool
+}
+
+// Con is a constraint of a model.
+// The coefficients are sparse: Coeffs[k] multiplies variable Vars[k].
+type Con struct {
+	Name   string
+	Vars   []int
+	Coeffs []float64
+	Lower  float64
+	Upper  float64
+}
+
+// NewModel returns an empty model.
+func NewModel() *Model {
+	return new(Model)
+}
+
+// AddVar adds a continuous variable and returns its index.
+func (m *Model) AddVar(name string, obj, lower, upper float64) int {
+	m.Vars = append(m.Vars, Var{Name: name, Obj: obj, Lower: lower, Upper: upper})
+	return len(m.Vars) - 1
+}
+
+// AddIntVar adds an integer variable and returns its index.
+func (m *Model) AddIntVar(name string, obj, lower, upper float64) int {
+	j := m.AddVar(name, obj, lower, upper)
+	m.Vars[j].Int = true
+	return j
+}
+
+// AddCon adds the constraint
+//	lower <= sum_k coeffs[k] x[vars[k]] <= upper
+// and returns its index.
+func (m *Model) AddCon(name string, lower, upper float64, vars []int, coeffs []float64) int {
+	con := Con{Name: name, Lower: lower, Upper: upper}
+	con.Vars = append([]int(nil), vars...)
+	con.Coeffs = append([]float64(nil), coeffs...)
+	m.Cons = append(m.Cons, con)
+	return len(m.Cons) - 1
+}
+
+// Obj returns the objective value of the model at x.
+func (m *Model) Obj(x []float64) float64 {
+	obj := m.Const
+	for j, v := range m.Vars {
+		obj += v.Obj * x[j]
+	}
+	return obj
+}
+
+// Activity returns the value of the linear expression of the constraint at x.
+func (con *Con) Activity(x []float64) float64 {
+	var a float64
+	for k, j := range con.Vars {
+		a += con.Coeffs[k] * x[j]
+	}
+	return a
+}
+
+// The correspondence between a model and its dictionary.
+//
+// The non-basic variables of the dictionary are the shifted variables of the model.
+// Variable j of the model is
+//	off[j] + sum_k signs[j][k] x[cols[j][k]].
+// The basic variables are slacks for the finite upper and lower sides
+// of each constraint, then the finite upper bounds of variables
+// which also have a finite lower bound.
+type modelLayout struct {
+	off   []float64
+	cols  [][]int
+	signs [][]float64
+	// Labels of the slacks for the upper and lower sides of each constraint,
+	// or -1 if that side is infinite.
+	upper, lower []int
+	// Label of the slack for the upper bound of each variable, or -1.
+	bound []int
+	m, n  int
+}
+
+func (m *Model) layout() *modelLayout {
+	p := len(m.Vars)
+	l := &modelLayout{
+		off:   make([]float64, p),
+		cols:  make([][]int, p),
+		signs: make([][]float64, p),
+		upper: make([]int, len(m.Cons)),
+		lower: make([]int, len(m.Cons)),
+		bound: make([]int, p),
+	}
+	for j, v := range m.Vars {
+		switch {
+		case !math.IsInf(v.Lower, -1):
+			l.off[j] = v.Lower
+			l.cols[j], l.signs[j] = []int{l.n}, []float64{1}
+			l.n++
+		case !math.IsInf(v.Upper, 1):
+			l.off[j] = v.Upper
+			l.cols[j], l.signs[j] = []int{l.n}, []float64{-1}
+			l.n++
+		default:
+			// Free variable is the difference of two variables.
+			l.cols[j], l.signs[j] = []int{l.n, l.n + 1}, []float64{1, -1}
+			l.n += 2
+		}
+	}
+	for i, con := range m.Cons {
+		l.upper[i], l.lower[i] = -1, -1
+		if !math.IsInf(con.Upper, 1) {
+			l.upper[i] = l.n + l.m
+			l.m++
+		}
+		if !math.IsInf(con.Lower, -1) {
+			l.lower[i] = l.n + l.m
+			l.m++
+		}
+	}
+	for j, v := range m.Vars {
+		l.bound[j] = -1
+		if !math.IsInf(v.Lower, -1) && !math.IsInf(v.Upper, 1) {
+			l.bound[j] = l.n + l.m
+			l.m++
+		}
+	}
+	return l
+}
+
+// Returns the variables of the model given the values of the variables
+// of the dictionary, indexed by label.
+func (l *modelLayout) x(vals []float64) []float64 {
+	x := make([]float64, len(l.off))
+	for j := range x {
+		x[j] = l.off[j]
+		for t, col := range l.cols[j] {
+			x[j] += l.signs[j][t] * vals[col]
+		}
+	}
+	return x
+}
+
+// Dict returns a dictionary describing the model.
+// The variables of the model are shifted and split as necessary
+// so that all variables of the dictionary are non-negative.
+// The non-basic variables are labelled from 0 and followed by the basic variables.
+// The objective is negated if the model is a minimization.
+func (m *Model) Dict() *Dict {
+	l := m.layout()
+	dict := NewDict(l.m, l.n)
+	for j := range dict.NonBasic {
+		dict.NonBasic[j] = j
+	}
+	for i := range dict.Basic {
+		dict.Basic[i] = l.n + i
+	}
+	sense := 1.0
+	if m.Min {
+		sense = -1
+	}
+
+	// Adds sign * (con - rhs) to row.
+	addRow := func(row int, con Con, rhs, sign float64) {
+		dict.B[row] = -sign * rhs
+		for k, j := range con.Vars {
+			a := sign * con.Coeffs[k]
+			dict.B[row] += a * l.off[j]
+			for t, col := range l.cols[j] {
+				dict.A[row][col] += a * l.signs[j][t]
+			}
+		}
+	}
+	for i, con := range m.Cons {
+		if l.upper[i] >= 0 {
+			// Upper - con >= 0
+			addRow(l.upper[i]-l.n, con, con.Upper, -1)
+		}
+		if l.lower[i] >= 0 {
+			// con - Lower >= 0
+			addRow(l.lower[i]-l.n, con, con.Lower, 1)
+		}
+	}
+	for j, v := range m.Vars {
+		if l.bound[j] >= 0 {
+			// Upper - Lower - x' >= 0
+			i := l.bound[j] - l.n
+			dict.B[i] = v.Upper - v.Lower
+			dict.A[i][l.cols[j][0]] = -1
+		}
+	}
+
+	dict.D = sense * m.Const
+	for j, v := range m.Vars {
+		dict.D += sense * v.Obj * l.off[j]
+		for t, col := range l.cols[j] {
+			dict.C[col] += sense * v.Obj * l.signs[j][t]
+		}
+	}
+	return dict
+}
+
+// ModelSoln is the solution of a model.
+//
+// The duals and reduced costs give the rate of change of the objective
+// with respect to the bounds of each constraint and variable.
+// The reduced costs satisfy
+//	RedCosts[j] = Vars[j].Obj - sum_i Duals[i] A[i][j].
+type ModelSoln struct {
+	X        []float64
+	Obj      float64
+	Duals    []float64
+	RedCosts []float64
+}
+
+// Soln returns the solution of the model associated with a dictionary
+// which was obtained from Dict (and subsequently pivoted).
+func (m *Model) Soln(dict *Dict) *ModelSoln {
+	l := m.layout()
+	p := len(m.Vars)
+	vals := dict.values()
+	// Reduced cost of each label in the dictionary.
+	red := make([]float64, len(vals))
+	for j, lbl := range dict.NonBasic {
+		red[lbl] = dict.C[j]
+	}
+	sense := 1.0
+	if m.Min {
+		sense = -1
+	}
+
+	s := &ModelSoln{
+		X:        l.x(vals),
+		Duals:    make([]float64, len(m.Cons)),
+		RedCosts: make([]float64, p),
+	}
+	for j := range m.Vars {
+		// Variable at lower bound (or upper bound if only bounded above).
+		d := l.signs[j][0] * red[l.cols[j][0]]
+		if len(l.cols[j]) > 1 && d == 0 {
+			d = l.signs[j][1] * red[l.cols[j][1]]
+		}
+		if l.bound[j] >= 0 {
+			// Variable at upper bound.
+			d -= red[l.bound[j]]
+		}
+		s.RedCosts[j] = sense * d
+	}
+	for i := range m.Cons {
+		var y float64
+		if l.upper[i] >= 0 {
+			y -= red[l.upper[i]]
+		}
+		if l.lower[i] >= 0 {
+			y += red[l.lower[i]]
+		}
+		s.Duals[i] = sense * y
+	}
+	s.Obj = m.Obj(s.X)
+	return s
+}
+
+// SolveModel solves the linear program described by a model.
+// Integer restrictions are ignored.
+func SolveModel(m *Model) (*ModelSoln, error) {
+	final, err := Solve(m.Dict())
+	if err != nil {
+		return nil, err
+	}
+	return m.Soln(final), nil
+}
@@ -0,0 +1,88 @@
+package lp_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/jvlmdr/golp/lp"
+)
+
+func ExampleSolveModel() {
+	inf := math.Inf(1)
+	m := lp.NewModel()
+	m.Min = true
+	// min_{x, y} 2 x + 3 y
+	x := m.AddVar("x", 2, 1, 10)
+	y := m.AddVar("y", 3, math.Inf(-1), inf)
+	// subject to
+	// x + y >= 4
+	// x - y <= 1
+	m.AddCon("sum", 4, inf, []int{x, y}, []float64{1, 1})
+	m.AddCon("diff", math.Inf(-1), 1, []int{x, y}, []float64{1, -1})
+
+	s, err := lp.SolveModel(m)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+	fmt.Printf("%.6g at %.6g\n", s.Obj, s.X)
+	fmt.Printf("duals %.6g\n", s.Duals)
+	// Output:
+	// 9.5 at [2.5 1.5]
+	// duals [2.5 -0.5]
+}
+
+func TestSolveModel_equality(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 200; trial++ {
+		// Random equalities which are satisfied by x.
+		m := lp.NewModel()
+		m.Min = r.Intn(2) == 0
+		n := 2 + r.Intn(4)
+		x := make([]float64, n)
+		for j := range x {
+			m.AddVar("", float64(r.Intn(11)-5), 0, 5)
+			x[j] = float64(r.Intn(6))
+		}
+		var rows [][]float64
+		for i := 1 + r.Intn(3); i > 0; i-- {
+			a := make([]float64, n)
+			for j := range a {
+				a[j] = float64(r.Intn(7) - 3)
+			}
+			rows = append(rows, a)
+		}
+		// Add redundant rows: copies and sums of the others.
+		for i := r.Intn(3); i > 0; i-- {
+			a := append([]float64(nil), rows[r.Intn(len(rows))]...)
+			if r.Intn(2) == 0 {
+				for j, b := range rows[r.Intn(len(rows))] {
+					a[j] += b
+				}
+			}
+			rows = append(rows, a)
+		}
+		vars := make([]int, n)
+		for j := range vars {
+			vars[j] = j
+		}
+		for _, a := range rows {
+			con := lp.Con{Vars: vars, Coeffs: a}
+			act := con.Activity(x)
+			m.AddCon("", act, act, vars, a)
+		}
+
+		s, err := lp.SolveModel(m)
+		if err != nil {
+			t.Fatalf("trial %d: %v", trial, err)
+		}
+		for i, con := range m.Cons {
+			if act := con.Activity(s.X); math.Abs(act-con.Lower) > 1e-6 {
+				t.Errorf("trial %d: constraint %d: got %g, want %g", trial, i, act, con.Lower)
+			}
+		}
+		checkDuals(t, m, s, 1e-6)
+	}
+}
@@ -0,0 +1,184 @@
+package lp
+
+import (
+	"fmt"
+	"math"
+)
+
+// The functions in this file modify the problem described by a dictionary,
+// which may have been pivoted (for example, a final dictionary).
+// Constraints are identified by the label of their slack variable,
+// that is, the basic variable of the row in the original dictionary.
+// The modified dictionary retains the basis of the given dictionary
+// so that solving it (see Options.Basis) is warm-started.
+// The dictionary is not modified.
+// An error is returned if a label is not in the dictionary.
+
+// AddRow returns a dictionary with the additional constraint
+//	b + sum_k coeffs[k] x[labels[k]] >= 0
+// and the label of its slack variable.
+// The constraint is re-expressed in terms of the current non-basic variables
+// and its slack variable is basic.
+func (dict *Dict) AddRow(labels []int, coeffs []float64, b float64) (*Dict, int, error) {
+	if err := dict.checkTerms(labels, coeffs); err != nil {
+		return nil, 0, err
+	}
+	a, b := dict.express(labels, coeffs, b)
+	return appendRows(dict, [][]float64{a}, []float64{b}), numVars(dict), nil
+}
+
+// AddUpperBound returns a dictionary with the additional constraint
+//	x[label] <= upper
+// and the label of its slack variable.
+// The bound can later be changed to upper + delta
+// using AddRHS with the label of the slack variable.
+// The lower bound of a variable which is non-basic
+// in the original dictionary can be changed using AddRHS
+// with the label of the variable.
+func (dict *Dict) AddUpperBound(label int, upper float64) (*Dict, int, error) {
+	return dict.AddRow([]int{label}, []float64{-1}, upper)
+}
+
+// AddVar returns a dictionary with an additional non-negative variable
+// and the label of the new variable.
+// The variable has coefficient obj in the objective
+// and coeffs[k] in the constraint of the variable labels[k],
+// which is the row of that variable in the original dictionary.
+// The new variable is non-basic.
+func (dict *Dict) AddVar(obj float64, labels []int, coeffs []float64) (*Dict, int, error) {
+	if err := dict.checkTerms(labels, coeffs); err != nil {
+		return nil, 0, err
+	}
+	m, n := len(dict.Basic), len(dict.NonBasic)
+	// If the slack y of a constraint becomes y + a x,
+	// the variables of the dictionary are in terms of y' = y - a x.
+	// Basic variables are incremented by a x
+	// and non-basic variables y' are substituted.
+	col := make([]float64, m)
+	c := obj
+	for k, lbl := range labels {
+		a := coeffs[k]
+		if i, found := find(lbl, dict.Basic); found {
+			col[i] += a
+			continue
+		}
+		j, _ := find(lbl, dict.NonBasic)
+		for i := range col {
+			col[i] -= a * dict.A[i][j]
+		}
+		c -= a * dict.C[j]
+	}
+
+	dst := NewDict(m, n+1)
+	copy(dst.Basic, dict.Basic)
+	copy(dst.NonBasic, dict.NonBasic)
+	lbl := numVars(dict)
+	dst.NonBasic[n] = lbl
+	for i := range dict.A {
+		copy(dst.A[i], dict.A[i])
+		dst.A[i][n] = col[i]
+	}
+	copy(dst.B, dict.B)
+	copy(dst.C, dict.C)
+	dst.C[n] = c
+	dst.D = dict.D
+	return dst, lbl, nil
+}
+
+// RemoveRow returns a dictionary without the constraint
+// of the variable with the given label, which is removed from the problem.
+// If the variable is non-basic, it is first pivoted into the basic set
+// using the row with the largest coefficient.
+// The returned dictionary may not be feasible.
+func (dict *Dict) RemoveRow(label int) (*Dict, error) {
+	i, found := find(label, dict.Basic)
+	if !found {
+		j, found := find(label, dict.NonBasic)
+		if !found {
+			return nil, fmt.Errorf("no variable with label %d", label)
+		}
+		var max float64
+		i = -1
+		for k := range dict.Basic {
+			if a := math.Abs(dict.A[k][j]); a > max {
+				i, max = k, a
+			}
+		}
+		if i < 0 {
+			return nil, fmt.Errorf("variable %d does not appear in any constraint", label)
+		}
+		dict = dict.Pivot(j, i)
+	}
+
+	m, n := len(dict.Basic), len(dict.NonBasic)
+	dst := NewDict(m-1, n)
+	copy(dst.Basic, dict.Basic[:i])
+	copy(dst.Basic[i:], dict.Basic[i+1:])
+	copy(dst.NonBasic, dict.NonBasic)
+	for k := range dst.A {
+		src := k
+		if k >= i {
+			src++
+		}
+		copy(dst.A[k], dict.A[src])
+		dst.B[k] = dict.B[src]
+	}
+	copy(dst.C, dict.C)
+	dst.D = dict.D
+	return dst, nil
+}
+
+// AddRHS returns a dictionary in which delta is added to the constant
+// of the constraint of the variable with the given label.
+// If the variable is non-basic in the original dictionary,
+// this changes its lower bound from 0 to -delta
+// (and the variable is offset by delta).
+func (dict *Dict) AddRHS(label int, delta float64) (*Dict, error) {
+	dst := dict.Clone()
+	if i, found := find(label, dict.Basic); found {
+		dst.B[i] += delta
+		return dst, nil
+	}
+	j, found := find(label, dict.NonBasic)
+	if !found {
+		return nil, fmt.Errorf("no variable with label %d", label)
+	}
+	// Substitute y' = y - delta.
+	for i := range dst.B {
+		dst.B[i] -= delta * dict.A[i][j]
+	}
+	dst.D -= delta * dict.C[j]
+	return dst, nil
+}
+
+// AddObj returns a dictionary in which delta times
+// the variable with the given label is added to the objective.
+func (dict *Dict) AddObj(label int, delta float64) (*Dict, error) {
+	if err := dict.checkTerms([]int{label}, []float64{delta}); err != nil {
+		return nil, err
+	}
+	dst := dict.Clone()
+	c, d := dict.express([]int{label}, []float64{delta}, 0)
+	for j := range dst.C {
+		dst.C[j] += c[j]
+	}
+	dst.D += d
+	return dst, nil
+}
+
+// Returns an error if the lengths of labels and coeffs differ
+// or a label is not in the dictionary.
+func (dict *Dict) checkTerms(labels []int, coeffs []float64) error {
+	if len(labels) != len(coeffs) {
+		return fmt.Errorf("%d labels but %d coefficients", len(labels), len(coeffs))
+	}
+	for _, lbl := range labels {
+		if _, found := find(lbl, dict.Basic); found {
+			continue
+		}
+		if _, found := find(lbl, dict.NonBasic); !found {
+			return fmt.Errorf("no variable with label %d", lbl)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package lp_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/jvlmdr/golp/lp"
+)
+
+func ExampleDict_AddRow() {
+	dict := new(lp.Dict)
+	dict.NonBasic = []int{0, 1}
+	dict.Basic = []int{2, 3, 4}
+	// max_{x, y >= 0} x + 2 y
+	dict.C = []float64{1, 2}
+	// subject to
+	// -x + y <= 1,     x -  y +  1 >= 0
+	// 3x + 2y <= 12, -3x - 2y + 12 >= 0
+	// 2x + 3y <= 12, -2x - 3y + 12 >= 0
+	dict.A = make([][]float64, 3)
+	dict.B = make([]float64, 3)
+	dict.A[0], dict.B[0] = []float64{1, -1}, 1
+	dict.A[1], dict.B[1] = []float64{-3, -2}, 12
+	dict.A[2], dict.B[2] = []float64{-2, -3}, 12
+
+	dict, err := lp.Solve(dict)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+	fmt.Printf("%.6g at %.6g\n", dict.Obj(), dict.Soln()[:2])
+
+	// Add the constraint y <= 2, -y + 2 >= 0.
+	dict, _, err = dict.AddRow([]int{1}, []float64{-1}, 2)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+	dict, err = lp.SolveOpts(dict, lp.Options{Basis: dict.Basis()})
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+	fmt.Printf("%.6g at %.6g\n", dict.Obj(), dict.Soln()[:2])
+	// Output:
+	// 7.4 at [1.8 2.8]
+	// 6.66667 at [2.66667 2]
+}
+
+func TestDict_modify(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 200; trial++ {
+		m, n := 1+r.Intn(4), 1+r.Intn(4)
+		orig := randBoundedDict(r, m, n)
+		final, err := lp.Solve(orig)
+		if err != nil {
+			t.Fatalf("trial %d: %v", trial, err)
+		}
+
+		// Modifying the original dictionary is equivalent
+		// to modifying the problem from scratch.
+		structural := r.Intn(n)
+		row := n + r.Intn(m+n)
+		var modify func(*lp.Dict) (*lp.Dict, error)
+		var desc string
+		switch op := r.Intn(6); op {
+		case 0:
+			labels := []int{structural, (structural + 1) % n}
+			coeffs := []float64{r.NormFloat64(), r.NormFloat64()}
+			b := r.Float64() - 0.5
+			desc = fmt.Sprintf("AddRow(%v, %.3g, %.3g)", labels, coeffs, b)
+			modify = func(d *lp.Dict) (*lp.Dict, error) {
+				d, _, err := d.AddRow(labels, coeffs, b)
+				return d, err
+			}
+		case 1:
+			// Include a bound row to keep the problem bounded.
+			labels := []int{row, n + m + r.Intn(n)}
+			coeffs := []float64{r.NormFloat64(), -1 - r.Float64()}
+			obj := r.NormFloat64()
+			desc = fmt.Sprintf("AddVar(%.3g, %v, %.3g)", obj, labels, coeffs)
+			modify = func(d *lp.Dict) (*lp.Dict, error) {
+				d, _, err := d.AddVar(obj, labels, coeffs)
+				return d, err
+			}
+		case 2:
+			desc = fmt.Sprintf("RemoveRow(%d)", row)
+			modify = func(d *lp.Dict) (*lp.Dict, error) { return d.RemoveRow(row) }
+		case 3:
+			delta := r.NormFloat64()
+			desc = fmt.Sprintf("AddRHS(%d, %.3g)", row, delta)
+			modify = func(d *lp.Dict) (*lp.Dict, error) { return d.AddRHS(row, delta) }
+		case 4:
+			delta := r.NormFloat64()
+			desc = fmt.Sprintf("AddObj(%d, %.3g)", structural, delta)
+			modify = func(d *lp.Dict) (*lp.Dict, error) { return d.AddObj(structural, delta) }
+		case 5:
+			// Add an upper bound and then change it.
+			upper, delta := 2*r.Float64(), r.NormFloat64()
+			desc = fmt.Sprintf("AddUpperBound(%d, %.3g) then AddRHS(%.3g)", structural, upper, delta)
+			modify = func(d *lp.Dict) (*lp.Dict, error) {
+				d, slack, err := d.AddUpperBound(structural, upper)
+				if err != nil {
+					return nil, err
+				}
+				return d.AddRHS(slack, delta)
+			}
+		}
+
+		scratch, err := modify(orig)
+		if err != nil {
+			t.Fatalf("trial %d: %s: %v", trial, desc, err)
+		}
+		want, wantErr := lp.Solve(scratch)
+		warm, err := modify(final)
+		if err != nil {
+			t.Fatalf("trial %d: %s: %v", trial, desc, err)
+		}
+		got, err := lp.SolveOpts(warm, lp.Options{Basis: warm.Basis()})
+		if (err == nil) != (wantErr == nil) {
+			t.Fatalf("trial %d: %s: got error %v, want %v", trial, desc, err, wantErr)
+		}
+		if err == nil && math.Abs(got.Obj()-want.Obj()) > 1e-6 {
+			t.Errorf("trial %d: %s: got objective %g, want %g", trial, desc, got.Obj(), want.Obj())
+		}
+	}
+}
+
+func TestDict_modifyLabels(t *testing.T) {
+	dict := randBoundedDict(rand.New(rand.NewSource(1)), 2, 2)
+	calls := map[string]func() error{
+		"AddRow": func() error {
+			_, _, err := dict.AddRow([]int{0, 7}, []float64{1, 1}, 1)
+			return err
+		},
+		"AddVar": func() error {
+			_, _, err := dict.AddVar(1, []int{7}, []float64{1})
+			return err
+		},
+		"AddUpperBound": func() error {
+			_, _, err := dict.AddUpperBound(7, 1)
+			return err
+		},
+		"RemoveRow": func() error {
+			_, err := dict.RemoveRow(7)
+			return err
+		},
+		"AddRHS": func() error {
+			_, err := dict.AddRHS(7, 1)
+			return err
+		},
+		"AddObj": func() error {
+			_, err := dict.AddObj(7, 1)
+			return err
+		},
+	}
+	const want = "no variable with label 7"
+	for name, call := range calls {
+		if err := call(); err == nil || err.Error() != want {
+			t.Errorf("%s: want error %q, got %v", name, want, err)
+		}
+	}
+	if _, _, err := dict.AddRow([]int{0}, nil, 1); err == nil {
+		t.Errorf("AddRow: want error for missing coefficients")
+	}
+}
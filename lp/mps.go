@@ -0,0 +1,603 @@
+package lp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ReadMPSFrom reads a model in fixed MPS format.
+// Fields must be in the standard columns and names may contain spaces.
+//
+// The sections NAME, OBJSENSE, ROWS, COLUMNS, RHS, RANGES, BOUNDS and ENDATA
+// are supported. Variables between INTORG and INTEND markers are integer.
+// The first N row is the objective and the objective is minimized
+// unless OBJSENSE specifies MAX.
+func ReadMPSFrom(r io.Reader) (*Model, error) {
+	return readMPS(r, fixedFields)
+}
+
+// ReadFreeMPSFrom reads a model in free MPS format.
+// Fields are separated by white space and names may not contain spaces.
+func ReadFreeMPSFrom(r io.Reader) (*Model, error) {
+	return readMPS(r, strings.Fields)
+}
+
+// Start and end columns of the six fields of a fixed MPS line.
+var mpsCols = [][2]int{{1, 3}, {4, 12}, {14, 22}, {24, 36}, {39, 47}, {49, 61}}
+
+// Splits a fixed MPS line into its non-empty fields.
+func fixedFields(line string) []string {
+	var fields []string
+	for _, c := range mpsCols {
+		if c[0] >= len(line) {
+			break
+		}
+		f := strings.TrimSpace(line[c[0]:min(c[1], len(line))])
+		if f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// State of the MPS reader.
+type mpsReader struct {
+	m   *Model
+	obj string
+	// Index of each constraint by name, or -1 for the objective.
+	rows map[string]int
+	// Type of each constraint: E, L, G or N.
+	kinds []byte
+	// Right-hand side and range of each constraint.
+	rhs []float64
+	rng []float64
+	// Index of each variable by name.
+	cols map[string]int
+	// Whether the lower bound of a variable was given explicitly.
+	lowerSet []bool
+	intSec   bool
+}
+
+func readMPS(r io.Reader, split func(string) []string) (*Model, error) {
+	p := &mpsReader{
+		m:    NewModel(),
+		rows: make(map[string]int),
+		cols: make(map[string]int),
+	}
+	p.m.Min = true
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, math.MaxInt32)
+	var (
+		section string
+		lineNum int
+		ended   bool
+	)
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if len(line) == 0 || line[0] == '*' {
+			continue
+		}
+		var err error
+		if line[0] != ' ' && line[0] != '\t' {
+			// Section header.
+			words := strings.Fields(line)
+			if len(words) == 0 {
+				return nil, fmt.Errorf("line %d: no section name", lineNum)
+			}
+			section = words[0]
+			switch section {
+			case "NAME":
+				if len(words) > 1 {
+					p.m.Name = strings.TrimSpace(line[len("NAME"):])
+				}
+			case "OBJSENSE":
+				if len(words) > 1 {
+					err = p.objSense(words[1])
+				}
+			case "ROWS", "COLUMNS", "RHS", "RANGES", "BOUNDS":
+			case "ENDATA":
+				ended = true
+			default:
+				err = fmt.Errorf("unknown section %q", section)
+			}
+		} else {
+			fields := split(line)
+			switch section {
+			case "OBJSENSE":
+				if len(fields) != 1 {
+					err = fmt.Errorf("objective sense: want 1 field, got %d", len(fields))
+				} else {
+					err = p.objSense(fields[0])
+				}
+			case "ROWS":
+				err = p.row(fields)
+			case "COLUMNS":
+				err = p.column(fields)
+			case "RHS":
+				err = p.values(fields, p.rhs, true)
+			case "RANGES":
+				err = p.values(fields, p.rng, false)
+			case "BOUNDS":
+				err = p.bound(fields)
+			default:
+				err = errors.New("data outside of section")
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNum, err)
+		}
+		if ended {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !ended {
+		return nil, errors.New("missing ENDATA")
+	}
+	p.finish()
+	return p.m, nil
+}
+
+func (p *mpsReader) objSense(word string) error {
+	switch word {
+	case "MAX", "MAXIMIZE":
+		p.m.Min = false
+	case "MIN", "MINIMIZE":
+		p.m.Min = true
+	default:
+		return fmt.Errorf("unknown objective sense %q", word)
+	}
+	return nil
+}
+
+func (p *mpsReader) row(fields []string) error {
+	if len(fields) != 2 {
+		return fmt.Errorf("row: want 2 fields, got %d", len(fields))
+	}
+	kind, name := fields[0], fields[1]
+	if _, dup := p.rows[name]; dup {
+		return fmt.Errorf("duplicate row %q", name)
+	}
+	if kind == "N" && p.obj == "" {
+		p.obj = name
+		p.rows[name] = -1
+		return nil
+	}
+	switch kind {
+	case "E", "L", "G", "N":
+	default:
+		return fmt.Errorf("unknown row type %q", kind)
+	}
+	p.rows[name] = len(p.m.Cons)
+	p.m.Cons = append(p.m.Cons, Con{Name: name})
+	p.kinds = append(p.kinds, kind[0])
+	p.rhs = append(p.rhs, 0)
+	p.rng = append(p.rng, math.NaN())
+	return nil
+}
+
+func (p *mpsReader) column(fields []string) error {
+	if len(fields) >= 3 && fields[1] == "'MARKER'" {
+		switch fields[2] {
+		case "'INTORG'":
+			p.intSec = true
+		case "'INTEND'":
+			p.intSec = false
+		default:
+			return fmt.Errorf("unknown marker %s", fields[2])
+		}
+		return nil
+	}
+	if len(fields) != 3 && len(fields) != 5 {
+		return fmt.Errorf("column: want 3 or 5 fields, got %d", len(fields))
+	}
+	name := fields[0]
+	j, ok := p.cols[name]
+	if !ok {
+		j = len(p.m.Vars)
+		p.cols[name] = j
+		p.m.Vars = append(p.m.Vars, Var{Name: name, Upper: math.Inf(1), Int: p.intSec})
+		p.lowerSet = append(p.lowerSet, false)
+	}
+	for k := 1; k+1 < len(fields); k += 2 {
+		i, ok := p.rows[fields[k]]
+		if !ok {
+			return fmt.Errorf("unknown row %q", fields[k])
+		}
+		a, err := strconv.ParseFloat(fields[k+1], 64)
+		if err != nil {
+			return err
+		}
+		if i < 0 {
+			p.m.Vars[j].Obj = a
+			continue
+		}
+		con := &p.m.Cons[i]
+		con.Vars = append(con.Vars, j)
+		con.Coeffs = append(con.Coeffs, a)
+	}
+	return nil
+}
+
+// Reads RHS or RANGES entries into vals.
+// The name of the set may be omitted.
+func (p *mpsReader) values(fields []string, vals []float64, obj bool) error {
+	if len(fields)%2 == 1 {
+		fields = fields[1:]
+	}
+	if len(fields) != 2 && len(fields) != 4 {
+		return fmt.Errorf("want 2 or 4 values, got %d", len(fields))
+	}
+	for k := 0; k < len(fields); k += 2 {
+		i, ok := p.rows[fields[k]]
+		if !ok {
+			return fmt.Errorf("unknown row %q", fields[k])
+		}
+		v, err := strconv.ParseFloat(fields[k+1], 64)
+		if err != nil {
+			return err
+		}
+		if i < 0 {
+			if !obj {
+				return errors.New("range on objective")
+			}
+			// Right-hand side of objective is minus the constant.
+			p.m.Const = -v
+			continue
+		}
+		vals[i] = v
+	}
+	return nil
+}
+
+func (p *mpsReader) bound(fields []string) error {
+	if len(fields) < 2 {
+		return fmt.Errorf("bound: want at least 2 fields, got %d", len(fields))
+	}
+	kind := fields[0]
+	hasValue := true
+	switch kind {
+	case "FR", "MI", "PL", "BV":
+		hasValue = false
+	}
+	// Remove the name of the bound set if present.
+	want := 2
+	if hasValue {
+		want = 3
+	}
+	if len(fields) > want || kind == "BV" && len(fields) > 2 {
+		fields = fields[1:]
+	}
+	if kind == "BV" && len(fields) > 2 {
+		// Ignore value of binary bound.
+		fields = fields[:2]
+	}
+	if len(fields) != want {
+		return fmt.Errorf("bound %s: wrong number of fields", kind)
+	}
+	j, ok := p.cols[fields[1]]
+	if !ok {
+		return fmt.Errorf("unknown column %q", fields[1])
+	}
+	var val float64
+	if hasValue {
+		var err error
+		val, err = strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			return err
+		}
+	}
+	v := &p.m.Vars[j]
+	switch kind {
+	case "UP", "UI":
+		v.Upper = val
+		if val < 0 && v.Lower == 0 && !p.lowerSet[j] {
+			v.Lower = math.Inf(-1)
+		}
+	case "LO", "LI":
+		v.Lower = val
+		p.lowerSet[j] = true
+	case "FX":
+		v.Lower, v.Upper = val, val
+		p.lowerSet[j] = true
+	case "FR":
+		v.Lower, v.Upper = math.Inf(-1), math.Inf(1)
+		p.lowerSet[j] = true
+	case "MI":
+		v.Lower = math.Inf(-1)
+		p.lowerSet[j] = true
+	case "PL":
+		v.Upper = math.Inf(1)
+	case "BV":
+		v.Lower, v.Upper = 0, 1
+		p.lowerSet[j] = true
+	default:
+		return fmt.Errorf("unknown bound type %q", kind)
+	}
+	if kind == "UI" || kind == "LI" || kind == "BV" {
+		v.Int = true
+	}
+	return nil
+}
+
+// Sets the bounds of the constraints from their type, right-hand side and range.
+func (p *mpsReader) finish() {
+	inf := math.Inf(1)
+	for i := range p.m.Cons {
+		con := &p.m.Cons[i]
+		b, r := p.rhs[i], p.rng[i]
+		switch p.kinds[i] {
+		case 'E':
+			con.Lower, con.Upper = b, b
+			if r > 0 {
+				con.Upper = b + r
+			} else if r < 0 {
+				con.Lower = b + r
+			}
+		case 'L':
+			con.Lower, con.Upper = -inf, b
+			if !math.IsNaN(r) {
+				con.Lower = b - math.Abs(r)
+			}
+		case 'G':
+			con.Lower, con.Upper = b, inf
+			if !math.IsNaN(r) {
+				con.Upper = b + math.Abs(r)
+			}
+		case 'N':
+			con.Lower, con.Upper = -inf, inf
+		}
+	}
+}
+
+// WriteMPSTo writes a model in fixed MPS format.
+// Names must have at most 8 characters.
+// Variables and constraints without names are given names.
+func WriteMPSTo(w io.Writer, m *Model) error {
+	return writeMPS(w, m, false)
+}
+
+// WriteFreeMPSTo writes a model in free MPS format.
+// Names must not contain spaces.
+// Variables and constraints without names are given names.
+func WriteFreeMPSTo(w io.Writer, m *Model) error {
+	return writeMPS(w, m, true)
+}
+
+// Writes the lines of an MPS file.
+type mpsWriter struct {
+	w    *bufio.Writer
+	free bool
+}
+
+// Writes a data line with a type and name-value pairs.
+func (mw *mpsWriter) line(kind string, fields ...string) {
+	if mw.free {
+		mw.w.WriteString(" ")
+		if kind != "" {
+			mw.w.WriteString(kind + " ")
+		}
+		mw.w.WriteString(strings.Join(fields, " "))
+		mw.w.WriteString("\n")
+		return
+	}
+	b := []byte(" " + kind)
+	for k, f := range fields {
+		c := mpsCols[k+1]
+		for len(b) < c[0] {
+			b = append(b, ' ')
+		}
+		if k == 2 || k == 4 {
+			// Numbers are right aligned.
+			b = append(b, fmt.Sprintf("%*s", c[1]-c[0], f)...)
+		} else {
+			// Names are left aligned.
+			b = append(b, f...)
+		}
+	}
+	mw.w.Write(b)
+	mw.w.WriteString("\n")
+}
+
+// Formats a number to fit in a field.
+func (mw *mpsWriter) num(x float64) string {
+	s := strconv.FormatFloat(x, 'g', -1, 64)
+	if mw.free {
+		return s
+	}
+	for prec := 12; len(s) > 12 && prec > 0; prec-- {
+		s = strconv.FormatFloat(x, 'g', prec, 64)
+	}
+	return s
+}
+
+func writeMPS(w io.Writer, m *Model, free bool) error {
+	mw := &mpsWriter{bufio.NewWriter(w), free}
+	rows, cols, obj, err := mpsNames(m, free)
+	if err != nil {
+		return err
+	}
+	inf := math.Inf(1)
+
+	fmt.Fprintf(mw.w, "NAME          %s\n", m.Name)
+	if !m.Min {
+		mw.w.WriteString("OBJSENSE\n    MAX\n")
+	}
+
+	mw.w.WriteString("ROWS\n")
+	mw.line("N", obj)
+	for i, con := range m.Cons {
+		var kind string
+		switch {
+		case con.Lower == con.Upper:
+			kind = "E"
+		case !math.IsInf(con.Upper, 1):
+			kind = "L"
+		case !math.IsInf(con.Lower, -1):
+			kind = "G"
+		default:
+			kind = "N"
+		}
+		mw.line(kind, rows[i])
+	}
+
+	// Find coefficients of each column.
+	type entry struct {
+		row int
+		a   float64
+	}
+	entries := make([][]entry, len(m.Vars))
+	for i, con := range m.Cons {
+		for k, j := range con.Vars {
+			entries[j] = append(entries[j], entry{i, con.Coeffs[k]})
+		}
+	}
+	mw.w.WriteString("COLUMNS\n")
+	var intSec bool
+	for j, v := range m.Vars {
+		if v.Int != intSec {
+			marker := "'INTORG'"
+			if intSec {
+				marker = "'INTEND'"
+			}
+			mw.line("", "MARKER", "'MARKER'", "", marker)
+			intSec = v.Int
+		}
+		if v.Obj != 0 || len(entries[j]) == 0 {
+			mw.line("", cols[j], obj, mw.num(v.Obj))
+		}
+		for _, e := range entries[j] {
+			mw.line("", cols[j], rows[e.row], mw.num(e.a))
+		}
+	}
+	if intSec {
+		mw.line("", "MARKER", "'MARKER'", "", "'INTEND'")
+	}
+
+	mw.w.WriteString("RHS\n")
+	if m.Const != 0 {
+		mw.line("", "RHS", obj, mw.num(-m.Const))
+	}
+	for i, con := range m.Cons {
+		var b float64
+		switch {
+		case !math.IsInf(con.Upper, 1) && con.Lower != con.Upper:
+			b = con.Upper
+		case !math.IsInf(con.Lower, -1):
+			b = con.Lower
+		}
+		if b != 0 {
+			mw.line("", "RHS", rows[i], mw.num(b))
+		}
+	}
+
+	var ranges bool
+	for i, con := range m.Cons {
+		if math.IsInf(con.Lower, -1) || math.IsInf(con.Upper, 1) || con.Lower == con.Upper {
+			continue
+		}
+		if !ranges {
+			mw.w.WriteString("RANGES\n")
+			ranges = true
+		}
+		mw.line("", "RNG", rows[i], mw.num(con.Upper-con.Lower))
+	}
+
+	var bounds bool
+	bound := func(kind string, j int, vals ...float64) {
+		if !bounds {
+			mw.w.WriteString("BOUNDS\n")
+			bounds = true
+		}
+		fields := []string{"BND", cols[j]}
+		for _, x := range vals {
+			fields = append(fields, mw.num(x))
+		}
+		mw.line(kind, fields...)
+	}
+	for j, v := range m.Vars {
+		switch {
+		case v.Int && v.Lower == 0 && v.Upper == 1:
+			bound("BV", j)
+		case v.Lower == v.Upper:
+			bound("FX", j, v.Lower)
+		case math.IsInf(v.Lower, -1) && math.IsInf(v.Upper, 1):
+			bound("FR", j)
+		default:
+			if math.IsInf(v.Lower, -1) {
+				bound("MI", j)
+			} else if v.Lower != 0 || v.Upper < 0 {
+				bound("LO", j, v.Lower)
+			}
+			if v.Upper != inf {
+				bound("UP", j, v.Upper)
+			}
+		}
+	}
+	mw.w.WriteString("ENDATA\n")
+	return mw.w.Flush()
+}
+
+// Returns the names of the constraints, variables and objective.
+func mpsNames(m *Model, free bool) (rows, cols []string, obj string, err error) {
+	used := make(map[string]bool)
+	check := func(name string) error {
+		if free && strings.ContainsAny(name, " \t") {
+			return fmt.Errorf("name %q contains space", name)
+		}
+		if !free && len(name) > 8 {
+			return fmt.Errorf("name %q longer than 8 characters", name)
+		}
+		return nil
+	}
+	rows = make([]string, len(m.Cons))
+	for i, con := range m.Cons {
+		if err := check(con.Name); err != nil {
+			return nil, nil, "", err
+		}
+		rows[i] = con.Name
+		used[con.Name] = true
+	}
+	cols = make([]string, len(m.Vars))
+	for j, v := range m.Vars {
+		if err := check(v.Name); err != nil {
+			return nil, nil, "", err
+		}
+		cols[j] = v.Name
+	}
+	// Give names to unnamed constraints and variables.
+	for i := range rows {
+		for k := i + 1; rows[i] == ""; k++ {
+			if name := "R" + strconv.Itoa(k); !used[name] {
+				rows[i] = name
+				used[name] = true
+			}
+		}
+	}
+	colUsed := make(map[string]bool)
+	for _, name := range cols {
+		colUsed[name] = true
+	}
+	for j := range cols {
+		for k := j + 1; cols[j] == ""; k++ {
+			if name := "C" + strconv.Itoa(k); !colUsed[name] {
+				cols[j] = name
+				colUsed[name] = true
+			}
+		}
+	}
+	obj = "OBJ"
+	for k := 1; used[obj]; k++ {
+		obj = "OBJ" + strconv.Itoa(k)
+	}
+	return rows, cols, obj, nil
+}
@@ -0,0 +1,212 @@
+package lp_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jvlmdr/golp/lp"
+)
+
+const testMPS = `NAME          TESTPROB
+ROWS
+ N  COST
+ L  LIM1
+ G  LIM2
+ E  MYEQN
+COLUMNS
+    XONE      COST               1.0   LIM1               1.0
+    XONE      LIM2               1.0
+    MARKER                 'MARKER'                 'INTORG'
+    YTWO      COST               2.0   LIM1               1.0
+    YTWO      MYEQN             -1.0
+    MARKER                 'MARKER'                 'INTEND'
+    ZTHREE    COST              -1.0   MYEQN              1.0
+RHS
+    RHS1      LIM1               4.0   LIM2               1.0
+    RHS1      MYEQN              7.0
+BOUNDS
+ UP BND1      XONE               4.0
+ LO BND1      YTWO              -1.0
+ UP BND1      YTWO               1.0
+ENDATA
+`
+
+func ExampleReadMPSFrom() {
+	m, err := lp.ReadMPSFrom(strings.NewReader(testMPS))
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+	for _, v := range m.Vars {
+		fmt.Printf("%s in [%g, %g], int %v\n", v.Name, v.Lower, v.Upper, v.Int)
+	}
+	for _, con := range m.Cons {
+		fmt.Printf("%s in [%g, %g]\n", con.Name, con.Lower, con.Upper)
+	}
+	// Output:
+	// XONE in [0, 4], int false
+	// YTWO in [-1, 1], int true
+	// ZTHREE in [0, +Inf], int false
+	// LIM1 in [-Inf, 4]
+	// LIM2 in [1, +Inf]
+	// MYEQN in [7, 7]
+}
+
+func TestReadFreeMPSFrom(t *testing.T) {
+	fixed, err := lp.ReadMPSFrom(strings.NewReader(testMPS))
+	if err != nil {
+		t.Fatal(err)
+	}
+	free, err := lp.ReadFreeMPSFrom(strings.NewReader(testMPS))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(fixed, free) {
+		t.Errorf("free and fixed differ:\n%+v\n%+v", free, fixed)
+	}
+}
+
+func TestReadMPSFrom_error(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{strings.Replace(testMPS, "XONE      LIM2", "XONE      LIM3", 1), "line 9: "},
+		// White space which is not removed from the end of the line.
+		{"\v", "line 1: no section name"},
+		{"NAME test\n\f\n", "line 2: no section name"},
+		{"NAME test\nOBJSENSE\n \f\nROWS\n N obj\nENDATA\n", "line 3: objective sense: want 1 field, got 0"},
+	}
+	for _, c := range cases {
+		for _, read := range []func(io.Reader) (*lp.Model, error){lp.ReadMPSFrom, lp.ReadFreeMPSFrom} {
+			_, err := read(strings.NewReader(c.in))
+			if err == nil || !strings.HasPrefix(err.Error(), c.want) {
+				t.Errorf("%q: want error %q, got %v", c.in, c.want, err)
+			}
+		}
+	}
+}
+
+func TestReadMPSFrom_objSense(t *testing.T) {
+	in := "NAME test\nOBJSENSE\n    MAX\nROWS\n N obj\nENDATA\n"
+	for _, read := range []func(io.Reader) (*lp.Model, error){lp.ReadMPSFrom, lp.ReadFreeMPSFrom} {
+		m, err := read(strings.NewReader(in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m.Min {
+			t.Errorf("want maximization")
+		}
+	}
+}
+
+// Returns a random model with bounds and constraints of every type.
+func randFormatModel(r *rand.Rand) *lp.Model {
+	inf := math.Inf(1)
+	bounds := [][2]float64{{0, inf}, {-inf, inf}, {-inf, 3}, {1.5, 2}, {2, 2}, {0, 1}, {-1, inf}, {0, -1}}
+	// Constraints can not have empty ranges.
+	ranges := bounds[:len(bounds)-1]
+	m := lp.NewModel()
+	m.Name = "RAND"
+	m.Min = r.Intn(2) == 0
+	m.Const = float64(r.Intn(5)) / 4
+	n := 1 + r.Intn(6)
+	for j := 0; j < n; j++ {
+		b := bounds[r.Intn(len(bounds))]
+		v := lp.Var{Name: fmt.Sprintf("x%d", j), Lower: b[0], Upper: b[1], Int: r.Intn(2) == 0}
+		if r.Intn(2) == 0 {
+			v.Obj = float64(r.Intn(21)-10) / 3
+		}
+		m.Vars = append(m.Vars, v)
+	}
+	for i := r.Intn(5); i >= 0; i-- {
+		b := ranges[r.Intn(len(ranges))]
+		con := lp.Con{Name: fmt.Sprintf("c%d", i), Lower: b[0], Upper: b[1]}
+		for j := 0; j < n; j++ {
+			if r.Intn(2) == 0 {
+				con.Vars = append(con.Vars, j)
+				con.Coeffs = append(con.Coeffs, float64(r.Intn(21)-10)/7)
+			}
+		}
+		m.Cons = append(m.Cons, con)
+	}
+	return m
+}
+
+// Returns the model with the coefficients of each constraint sorted by variable
+// so that models can be compared.
+func canonModel(m *lp.Model) *lp.Model {
+	c := *m
+	c.Cons = make([]lp.Con, len(m.Cons))
+	for i, con := range m.Cons {
+		a := make([]float64, len(m.Vars))
+		for k, j := range con.Vars {
+			a[j] += con.Coeffs[k]
+		}
+		c.Cons[i] = lp.Con{Name: con.Name, Lower: con.Lower, Upper: con.Upper}
+		for j := range a {
+			if a[j] != 0 {
+				c.Cons[i].Vars = append(c.Cons[i].Vars, j)
+				c.Cons[i].Coeffs = append(c.Cons[i].Coeffs, a[j])
+			}
+		}
+	}
+	return &c
+}
+
+// Returns true if the models are equal up to the precision of fixed MPS.
+func approxModel(a, b *lp.Model) bool {
+	round := func(x float64) float64 {
+		y, _ := strconv.ParseFloat(strconv.FormatFloat(x, 'g', 8, 64), 64)
+		return y
+	}
+	a, b = canonModel(a), canonModel(b)
+	for _, m := range []*lp.Model{a, b} {
+		m.Const = round(m.Const)
+		for j := range m.Vars {
+			m.Vars[j].Obj = round(m.Vars[j].Obj)
+		}
+		for i := range m.Cons {
+			for k := range m.Cons[i].Coeffs {
+				m.Cons[i].Coeffs[k] = round(m.Cons[i].Coeffs[k])
+			}
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func TestWriteMPSTo(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 100; trial++ {
+		m := randFormatModel(r)
+		for _, free := range []bool{false, true} {
+			var b bytes.Buffer
+			write, read := lp.WriteMPSTo, lp.ReadMPSFrom
+			if free {
+				write, read = lp.WriteFreeMPSTo, lp.ReadFreeMPSFrom
+			}
+			if err := write(&b, m); err != nil {
+				t.Fatal(err)
+			}
+			text := b.String()
+			got, err := read(&b)
+			if err != nil {
+				t.Fatalf("%v:\n%s", err, text)
+			}
+			equal := reflect.DeepEqual(canonModel(got), canonModel(m))
+			if !free {
+				equal = approxModel(got, m)
+			}
+			if !equal {
+				t.Fatalf("free %v: round trip differs:\n%s\ngot  %+v\nwant %+v", free, text, canonModel(got), canonModel(m))
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package netflow
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jvlmdr/golp/lp"
+)
+
+// Model returns the minimum-cost flow problem as a linear program.
+// Variable k is the flow on arc k
+// and constraint i is the balance of node i.
+func (g *Graph) Model() *lp.Model {
+	m := lp.NewModel()
+	m.Min = true
+	for i, b := range g.Supply {
+		m.AddCon(fmt.Sprintf("node%d", i), b, b, nil, nil)
+	}
+	for k, arc := range g.Arcs {
+		j := m.AddVar(fmt.Sprintf("arc%d", k), arc.Cost, 0, arc.Capacity)
+		out, in := &m.Cons[arc.From], &m.Cons[arc.To]
+		out.Vars, out.Coeffs = append(out.Vars, j), append(out.Coeffs, 1)
+		in.Vars, in.Coeffs = append(in.Vars, j), append(in.Coeffs, -1)
+	}
+	return m
+}
+
+// FromModel returns the minimum-cost flow problem described by a model.
+// The model must be a minimization whose constraints are equalities
+// and whose variables have lower bound zero.
+// Each variable must have coefficient 1 in one constraint (the tail of the arc)
+// and -1 in another (the head) and must not appear in other constraints.
+func FromModel(m *lp.Model) (*Graph, error) {
+	if !m.Min {
+		return nil, errors.New("model is not a minimization")
+	}
+	if m.Const != 0 {
+		return nil, errors.New("model has constant objective")
+	}
+	g := NewGraph(len(m.Cons))
+	for i, con := range m.Cons {
+		if con.Lower != con.Upper {
+			return nil, fmt.Errorf("constraint %d is not an equality", i)
+		}
+		g.Supply[i] = con.Lower
+	}
+	g.Arcs = make([]Arc, len(m.Vars))
+	for k := range g.Arcs {
+		g.Arcs[k].From, g.Arcs[k].To = -1, -1
+	}
+	for i, con := range m.Cons {
+		if len(con.Vars) != len(con.Coeffs) {
+			return nil, fmt.Errorf("constraint %d: %d variables and %d coefficients", i, len(con.Vars), len(con.Coeffs))
+		}
+		for t, k := range con.Vars {
+			if k < 0 || k >= len(g.Arcs) {
+				return nil, fmt.Errorf("constraint %d: no variable %d", i, k)
+			}
+			arc := &g.Arcs[k]
+			switch a := con.Coeffs[t]; {
+			case a == 1 && arc.From < 0:
+				arc.From = i
+			case a == -1 && arc.To < 0:
+				arc.To = i
+			case a != 0:
+				return nil, fmt.Errorf("variable %d: coefficient %g in constraint %d", k, a, i)
+			}
+		}
+	}
+	for k, v := range m.Vars {
+		if v.Lower != 0 {
+			return nil, fmt.Errorf("variable %d: lower bound is %g", k, v.Lower)
+		}
+		if g.Arcs[k].From < 0 || g.Arcs[k].To < 0 {
+			return nil, fmt.Errorf("variable %d: not in two constraints", k)
+		}
+		g.Arcs[k].Cost, g.Arcs[k].Capacity = v.Obj, v.Upper
+	}
+	return g, nil
+}
@@ -0,0 +1,386 @@
+// Package netflow solves minimum-cost flow problems
+// using the network simplex method.
+//
+// The problem is
+//	min sum_k Arcs[k].Cost x[k]
+//	s.t. sum_{k: From = i} x[k] - sum_{k: To = i} x[k] = Supply[i]
+//	     0 <= x[k] <= Arcs[k].Capacity.
+// Nodes with negative supply have demand.
+// If the supplies and capacities are integers,
+// then the solution is integral.
+package netflow
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/jvlmdr/golp/lp"
+)
+
+// Graph is a network with supplies at its nodes.
+type Graph struct {
+	Supply []float64
+	Arcs   []Arc
+}
+
+// Arc is a directed arc of a network.
+// The capacity may be infinite.
+type Arc struct {
+	From     int
+	To       int
+	Cost     float64
+	Capacity float64
+}
+
+// NewGraph returns a graph with n nodes with zero supply.
+func NewGraph(n int) *Graph {
+	return &Graph{Supply: make([]float64, n)}
+}
+
+// AddNode adds a node and returns its index.
+func (g *Graph) AddNode(supply float64) int {
+	g.Supply = append(g.Supply, supply)
+	return len(g.Supply) - 1
+}
+
+// AddArc adds an arc and returns its index.
+func (g *Graph) AddArc(from, to int, cost, capacity float64) int {
+	g.Arcs = append(g.Arcs, Arc{from, to, cost, capacity})
+	return len(g.Arcs) - 1
+}
+
+// Flow is the solution of a minimum-cost flow problem.
+type Flow struct {
+	// Flow on each arc.
+	X    []float64
+	Cost float64
+	// The reduced cost of each arc
+	//	Cost + Potentials[From] - Potentials[To]
+	// is non-negative if the arc is empty
+	// and non-positive if it is saturated.
+	Potentials []float64
+}
+
+// Relative tolerance for the balance of the supplies.
+const balanceTol = 1e-9
+
+// Arc states.
+const (
+	atLower = iota
+	atUpper
+	inTree
+)
+
+// Network simplex state.
+// The artificial root is node n and the artificial arc of node i is m + i.
+type simplex struct {
+	g     *Graph
+	n, m  int
+	from  []int
+	to    []int
+	cost  []float64
+	cap   []float64
+	x     []float64
+	state []int
+	// Arcs of the spanning tree at each node.
+	adj [][]int
+	// Spanning tree rooted at the artificial root.
+	parent []int
+	// Arc joining each node to its parent.
+	pred  []int
+	depth []int
+	pot   []float64
+	eps   float64
+}
+
+// Solve finds a minimum-cost flow.
+// Pivots follow Bland's rule: the entering arc is the eligible arc
+// with the lowest index and ties for the leaving arc
+// are broken by lowest index.
+// Finding the entering arc scans the arcs in O(m) time
+// and updating the spanning tree takes time proportional to the size
+// of the subtree which is re-attached by the entering arc.
+// Returns an error if the supplies do not sum to zero
+// (within a relative tolerance),
+// if no flow satisfies the supplies
+// or if there is a cycle of negative cost and infinite capacity.
+func Solve(g *Graph) (*Flow, error) {
+	return SolveEps(g, lp.DefaultEps)
+}
+
+func SolveEps(g *Graph, eps float64) (*Flow, error) {
+	if err := g.check(); err != nil {
+		return nil, err
+	}
+	s := newSimplex(g)
+	s.eps = eps
+	for {
+		enter := s.entering()
+		if enter < 0 {
+			break
+		}
+		if err := s.pivot(enter); err != nil {
+			return nil, err
+		}
+	}
+	tol := balanceTol * g.scale()
+	for i := 0; i < s.n; i++ {
+		if s.x[s.m+i] > tol {
+			return nil, errors.New("infeasible problem")
+		}
+	}
+
+	f := &Flow{
+		X:          append([]float64(nil), s.x[:s.m]...),
+		Potentials: append([]float64(nil), s.pot[:s.n]...),
+	}
+	for k, arc := range g.Arcs {
+		f.Cost += arc.Cost * f.X[k]
+	}
+	return f, nil
+}
+
+// Returns the total absolute supply, or one if it is less.
+func (g *Graph) scale() float64 {
+	var sum float64
+	for _, b := range g.Supply {
+		sum += math.Abs(b)
+	}
+	return math.Max(sum, 1)
+}
+
+// Returns an error if the graph is not valid.
+func (g *Graph) check() error {
+	var total float64
+	for i, b := range g.Supply {
+		if math.IsNaN(b) || math.IsInf(b, 0) {
+			return fmt.Errorf("node %d: supply is %g", i, b)
+		}
+		total += b
+	}
+	if math.Abs(total) > balanceTol*g.scale() {
+		return fmt.Errorf("supplies sum to %g, want 0", total)
+	}
+	for k, arc := range g.Arcs {
+		switch {
+		case arc.From < 0 || arc.From >= len(g.Supply):
+			return fmt.Errorf("arc %d: no node %d", k, arc.From)
+		case arc.To < 0 || arc.To >= len(g.Supply):
+			return fmt.Errorf("arc %d: no node %d", k, arc.To)
+		case math.IsNaN(arc.Cost) || math.IsInf(arc.Cost, 0):
+			return fmt.Errorf("arc %d: cost is %g", k, arc.Cost)
+		case !(arc.Capacity >= 0):
+			return fmt.Errorf("arc %d: capacity is %g", k, arc.Capacity)
+		}
+	}
+	return nil
+}
+
+// Creates the initial spanning tree of artificial arcs,
+// which carry the supply of each node to or from the root.
+// Their cost exceeds that of any path of real arcs.
+func newSimplex(g *Graph) *simplex {
+	n, m := len(g.Supply), len(g.Arcs)
+	s := &simplex{
+		g:      g,
+		n:      n,
+		m:      m,
+		from:   make([]int, m+n),
+		to:     make([]int, m+n),
+		cost:   make([]float64, m+n),
+		cap:    make([]float64, m+n),
+		x:      make([]float64, m+n),
+		state:  make([]int, m+n),
+		adj:    make([][]int, n+1),
+		parent: make([]int, n+1),
+		pred:   make([]int, n+1),
+		depth:  make([]int, n+1),
+		pot:    make([]float64, n+1),
+	}
+	var max float64
+	for k, arc := range g.Arcs {
+		s.from[k], s.to[k] = arc.From, arc.To
+		s.cost[k], s.cap[k] = arc.Cost, arc.Capacity
+		max = math.Max(max, math.Abs(arc.Cost))
+	}
+	bigM := 1 + float64(n+1)*max
+	for i, b := range g.Supply {
+		k := m + i
+		if b >= 0 {
+			s.from[k], s.to[k] = i, n
+		} else {
+			s.from[k], s.to[k] = n, i
+		}
+		s.cost[k], s.cap[k] = bigM, math.Inf(1)
+		s.x[k] = math.Abs(b)
+		s.state[k] = inTree
+		s.adj[i] = append(s.adj[i], k)
+		s.adj[n] = append(s.adj[n], k)
+	}
+	root := n
+	s.parent[root], s.pred[root], s.depth[root], s.pot[root] = -1, -1, 0, 0
+	s.hang(root)
+	return s
+}
+
+// Computes the parent, depth and potential of each node
+// in the subtree of u from those of u.
+func (s *simplex) hang(u int) {
+	stack := []int{u}
+	for len(stack) > 0 {
+		u := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, k := range s.adj[u] {
+			if k == s.pred[u] {
+				continue
+			}
+			v := s.from[k] + s.to[k] - u
+			s.parent[v], s.pred[v], s.depth[v] = u, k, s.depth[u]+1
+			// Reduced cost of tree arc is zero.
+			if s.from[k] == u {
+				s.pot[v] = s.pot[u] + s.cost[k]
+			} else {
+				s.pot[v] = s.pot[u] - s.cost[k]
+			}
+			stack = append(stack, v)
+		}
+	}
+}
+
+func (s *simplex) reduced(k int) float64 {
+	return s.cost[k] + s.pot[s.from[k]] - s.pot[s.to[k]]
+}
+
+// Returns the lowest-index arc whose reduced cost improves the objective
+// by more than eps, or -1 if the flow is optimal.
+func (s *simplex) entering() int {
+	for k, st := range s.state {
+		switch {
+		case st == atLower && s.reduced(k) < -s.eps:
+			return k
+		case st == atUpper && s.reduced(k) > s.eps:
+			return k
+		}
+	}
+	return -1
+}
+
+// Pushes flow around the cycle formed by the entering arc and the tree.
+func (s *simplex) pivot(enter int) error {
+	// The cycle is traversed along the entering arc from a to b,
+	// then up the tree from b to the apex and down to a.
+	a, b := s.from[enter], s.to[enter]
+	if s.state[enter] == atUpper {
+		a, b = b, a
+	}
+	type step struct {
+		arc     int
+		forward bool
+	}
+	cycle := []step{{enter, s.state[enter] == atLower}}
+	var down []step
+	u, v := b, a
+	for u != v {
+		if s.depth[u] >= s.depth[v] {
+			k := s.pred[u]
+			cycle = append(cycle, step{k, s.from[k] == u})
+			u = s.parent[u]
+		} else {
+			k := s.pred[v]
+			down = append(down, step{k, s.to[k] == v})
+			v = s.parent[v]
+		}
+	}
+	for i := len(down) - 1; i >= 0; i-- {
+		cycle = append(cycle, down[i])
+	}
+
+	// Find the arc which first reaches a bound, preferring the lowest index.
+	// The leaving arc is at its upper bound if its flow increases.
+	leave, delta, upper := -1, math.Inf(1), false
+	for _, st := range cycle {
+		r := s.x[st.arc]
+		if st.forward {
+			r = s.cap[st.arc] - s.x[st.arc]
+		}
+		if r < delta || (r == delta && st.arc < leave) {
+			leave, delta, upper = st.arc, r, st.forward
+		}
+	}
+	if math.IsInf(delta, 1) {
+		return errors.New("unbounded problem")
+	}
+	for _, st := range cycle {
+		if st.forward {
+			s.x[st.arc] += delta
+		} else {
+			s.x[st.arc] -= delta
+		}
+	}
+
+	if leave == enter {
+		// Entering arc moves to its other bound.
+		if s.state[enter] == atLower {
+			s.state[enter] = atUpper
+		} else {
+			s.state[enter] = atLower
+		}
+		return nil
+	}
+	s.state[enter] = inTree
+	if upper {
+		s.state[leave] = atUpper
+	} else {
+		s.state[leave] = atLower
+	}
+	s.update(enter, leave)
+	return nil
+}
+
+// Replaces the leaving arc with the entering arc in the spanning tree.
+// The subtree below the leaving arc is re-attached by the entering arc
+// and only its nodes are updated.
+func (s *simplex) update(enter, leave int) {
+	s.adj[s.from[leave]] = remove(s.adj[s.from[leave]], leave)
+	s.adj[s.to[leave]] = remove(s.adj[s.to[leave]], leave)
+	s.adj[s.from[enter]] = append(s.adj[s.from[enter]], enter)
+	s.adj[s.to[enter]] = append(s.adj[s.to[enter]], enter)
+
+	// The subtree below the leaving arc contains exactly one end
+	// of the entering arc.
+	sub := s.from[leave]
+	if s.pred[sub] != leave {
+		sub = s.to[leave]
+	}
+	u, v := s.to[enter], s.from[enter]
+	if s.inSubtree(s.from[enter], sub) {
+		u, v = v, u
+	}
+	// Hang the subtree from u by the entering arc to v.
+	s.parent[v], s.pred[v], s.depth[v] = u, enter, s.depth[u]+1
+	if s.from[enter] == u {
+		s.pot[v] = s.pot[u] + s.cost[enter]
+	} else {
+		s.pot[v] = s.pot[u] - s.cost[enter]
+	}
+	s.hang(v)
+}
+
+// Returns true if v is in the subtree of u.
+func (s *simplex) inSubtree(v, u int) bool {
+	for s.depth[v] > s.depth[u] {
+		v = s.parent[v]
+	}
+	return v == u
+}
+
+// Removes the first occurrence of x from xs.
+func remove(xs []int, x int) []int {
+	for i, y := range xs {
+		if y == x {
+			return append(xs[:i], xs[i+1:]...)
+		}
+	}
+	return xs
+}
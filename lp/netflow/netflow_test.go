@@ -0,0 +1,144 @@
+package netflow_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/jvlmdr/golp/lp"
+	"github.com/jvlmdr/golp/lp/netflow"
+)
+
+func ExampleSolve() {
+	// Ship 4 units from node 0 to node 3.
+	g := netflow.NewGraph(4)
+	g.Supply[0], g.Supply[3] = 4, -4
+	g.AddArc(0, 1, 2, 4)
+	g.AddArc(0, 2, 2, 2)
+	g.AddArc(1, 2, 1, 2)
+	g.AddArc(1, 3, 3, 3)
+	g.AddArc(2, 3, 1, 5)
+
+	f, err := netflow.Solve(g)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+	fmt.Println(f.Cost, f.X)
+	// Output:
+	// 14 [2 2 2 0 4]
+}
+
+// Returns a random graph which may be infeasible.
+func randGraph(r *rand.Rand) *netflow.Graph {
+	n := 2 + r.Intn(5)
+	g := netflow.NewGraph(n)
+	for k := r.Intn(3 * n); k > 0; k-- {
+		c := math.Inf(1)
+		if r.Intn(4) > 0 {
+			c = float64(r.Intn(6))
+		}
+		// Costs are non-negative on uncapacitated arcs
+		// to avoid unbounded problems.
+		cost := float64(r.Intn(11) - 5)
+		if math.IsInf(c, 1) {
+			cost = float64(r.Intn(6))
+		}
+		g.AddArc(r.Intn(n), r.Intn(n), cost, c)
+	}
+	for k := r.Intn(n); k > 0; k-- {
+		b := float64(r.Intn(5))
+		i, j := r.Intn(n), r.Intn(n)
+		g.Supply[i] += b
+		g.Supply[j] -= b
+	}
+	return g
+}
+
+func TestSolve(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 500; trial++ {
+		g := randGraph(r)
+		want, wantErr := lp.SolveModel(g.Model())
+		got, err := netflow.Solve(g)
+		if (err == nil) != (wantErr == nil) {
+			t.Fatalf("trial %d: got error %v, want %v", trial, err, wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if math.Abs(got.Cost-want.Obj) > 1e-6 {
+			t.Errorf("trial %d: got cost %g, want %g", trial, got.Cost, want.Obj)
+		}
+		for k, arc := range g.Arcs {
+			x := got.X[k]
+			if x != math.Floor(x) || x < 0 || x > arc.Capacity {
+				t.Errorf("trial %d: arc %d: flow %g", trial, k, x)
+			}
+			red := arc.Cost + got.Potentials[arc.From] - got.Potentials[arc.To]
+			if (x < arc.Capacity && red < 0) || (x > 0 && red > 0) {
+				t.Errorf("trial %d: arc %d: flow %g with reduced cost %g", trial, k, x, red)
+			}
+		}
+		net := make([]float64, len(g.Supply))
+		for k, arc := range g.Arcs {
+			net[arc.From] += got.X[k]
+			net[arc.To] -= got.X[k]
+		}
+		for i, b := range g.Supply {
+			if net[i] != b {
+				t.Errorf("trial %d: node %d: net flow %g, want %g", trial, i, net[i], b)
+			}
+		}
+	}
+}
+
+func TestFromModel(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 100; trial++ {
+		g := randGraph(r)
+		h, err := netflow.FromModel(g.Model())
+		if err != nil {
+			t.Fatalf("trial %d: %v", trial, err)
+		}
+		if fmt.Sprint(h) != fmt.Sprint(g) {
+			t.Fatalf("trial %d: got %v, want %v", trial, h, g)
+		}
+	}
+}
+
+func TestSolve_fractional(t *testing.T) {
+	// The supplies do not sum to exactly zero in floating point.
+	g := netflow.NewGraph(0)
+	a, b, c := g.AddNode(0.1), g.AddNode(0.2), g.AddNode(-0.3)
+	g.AddArc(a, c, 1, math.Inf(1))
+	g.AddArc(b, c, 2, math.Inf(1))
+	g.AddArc(a, b, 0, math.Inf(1))
+	f, err := netflow.Solve(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 0.5; math.Abs(f.Cost-want) > 1e-9 {
+		t.Errorf("got cost %g, want %g", f.Cost, want)
+	}
+
+	g.Supply[c] = -0.4
+	if _, err := netflow.Solve(g); err == nil {
+		t.Errorf("want error for unbalanced supplies")
+	}
+}
+
+func TestFromModel_invalid(t *testing.T) {
+	m := netflow.NewGraph(2).Model()
+	m.AddVar("x", 1, 0, 1)
+	m.Cons[0].Vars = []int{0, 1}
+	m.Cons[0].Coeffs = []float64{1, 1}
+	if _, err := netflow.FromModel(m); err == nil || err.Error() != "constraint 0: no variable 1" {
+		t.Errorf("want error for variable 1, got %v", err)
+	}
+	m.Cons[0].Vars = []int{0}
+	if _, err := netflow.FromModel(m); err == nil || err.Error() != "constraint 0: 1 variables and 2 coefficients" {
+		t.Errorf("want error for coefficients, got %v", err)
+	}
+}
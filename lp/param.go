@@ -0,0 +1,169 @@
+package lp
+
+import (
+	"fmt"
+	"math"
+)
+
+// Breakpoint is a point at which the optimal basis of a parametric
+// linear program changes.
+// The optimal value is piecewise linear in the parameter t:
+// from T until the next breakpoint it is
+//	Obj + Slope (t - T).
+type Breakpoint struct {
+	// Parameter at which the basis becomes optimal.
+	T float64
+	// Optimal value at T.
+	Obj float64
+	// Rate of change of the optimal value after T.
+	Slope float64
+	// Basis which is optimal from T until the next breakpoint.
+	Basis *Basis
+}
+
+// ParamRHS traces the optimal value of the problem
+// in which B is replaced by B + t dir as t goes from 0 to tmax.
+// The vector dir is indexed by the rows of the dictionary.
+// The optimal value is concave in t.
+//
+// Returns the breakpoints of the optimal value, the first of which is at zero,
+// and the largest t at which the problem is feasible, at most tmax.
+// Breakpoints are found using dual simplex pivots.
+// Returns an error if the problem is infeasible or unbounded at zero
+// or if dir does not have one value per row.
+func ParamRHS(dict *Dict, dir []float64, tmax float64) ([]Breakpoint, float64, error) {
+	return ParamRHSEps(dict, dir, tmax, DefaultEps)
+}
+
+func ParamRHSEps(dict *Dict, dir []float64, tmax float64, eps float64) ([]Breakpoint, float64, error) {
+	if len(dir) != len(dict.Basic) {
+		return nil, 0, fmt.Errorf("direction has %d values, want %d", len(dir), len(dict.Basic))
+	}
+	final, err := SolveEps(dict, eps)
+	if err != nil {
+		return nil, 0, err
+	}
+	pts, end := paramRHSEps(withParam(dict, dir, final.Basis(), eps), tmax, eps)
+	return pts, end, nil
+}
+
+// ParamObj traces the optimal value of the problem
+// in which C is replaced by C + t dir as t goes from 0 to tmax.
+// The vector dir is indexed by the non-basic variables of the dictionary.
+// The optimal value is convex in t.
+//
+// Returns the breakpoints of the optimal value, the first of which is at zero,
+// and the largest t at which the problem is bounded, at most tmax.
+// Breakpoints are found using primal simplex pivots
+// (dual simplex pivots in the dual dictionary).
+// Returns an error if the problem is infeasible or unbounded at zero
+// or if dir does not have one value per non-basic variable.
+func ParamObj(dict *Dict, dir []float64, tmax float64) ([]Breakpoint, float64, error) {
+	return ParamObjEps(dict, dir, tmax, DefaultEps)
+}
+
+func ParamObjEps(dict *Dict, dir []float64, tmax float64, eps float64) ([]Breakpoint, float64, error) {
+	if len(dir) != len(dict.NonBasic) {
+		return nil, 0, fmt.Errorf("direction has %d values, want %d", len(dir), len(dict.NonBasic))
+	}
+	final, err := SolveEps(dict, eps)
+	if err != nil {
+		return nil, 0, err
+	}
+	// The objective of the primal is the negative constants of the dual.
+	neg := make([]float64, len(dir))
+	for j, x := range dir {
+		neg[j] = -x
+	}
+	pts, end := paramRHSEps(withParam(dict.Dual(), neg, final.Dual().Basis(), eps), tmax, eps)
+	for k := range pts {
+		pts[k].Obj = -pts[k].Obj
+		pts[k].Slope = -pts[k].Slope
+		b := pts[k].Basis
+		b.Basic, b.NonBasic = b.NonBasic, b.Basic
+	}
+	return pts, end, nil
+}
+
+// Returns the dictionary with an additional non-basic variable t
+// with coefficients dir, expressed in the given basis.
+// The parameter t is the last non-basic variable and never enters the basic set.
+func withParam(dict *Dict, dir []float64, basis *Basis, eps float64) *Dict {
+	m, n := len(dict.Basic), len(dict.NonBasic)
+	aug := NewDict(m, n+1)
+	copy(aug.Basic, dict.Basic)
+	copy(aug.NonBasic, dict.NonBasic)
+	aug.NonBasic[n] = numVars(dict)
+	for i := range dict.A {
+		copy(aug.A[i], dict.A[i])
+		aug.A[i][n] = dir[i]
+	}
+	copy(aug.B, dict.B)
+	copy(aug.C, dict.C)
+	aug.D = dict.D
+	return aug.ToBasisEps(basis, eps)
+}
+
+// Traces the optimal value of a final dictionary with a parameter
+// (see withParam) from t = 0 to tmax.
+// At each breakpoint, a basic variable reaches zero and leaves the basis
+// by a dual simplex pivot.
+func paramRHSEps(dict *Dict, tmax, eps float64) (pts []Breakpoint, end float64) {
+	p := len(dict.NonBasic) - 1
+	var t float64
+	for {
+		pt := Breakpoint{
+			T:     t,
+			Obj:   dict.D + t*dict.C[p],
+			Slope: dict.C[p],
+			Basis: &Basis{
+				Basic:    append([]int(nil), dict.Basic...),
+				NonBasic: append([]int(nil), dict.NonBasic[:p]...),
+			},
+		}
+		if k := len(pts) - 1; k >= 0 && pts[k].T == t {
+			// Degenerate pivot.
+			pts[k] = pt
+		} else {
+			pts = append(pts, pt)
+		}
+
+		// Find the basic variable which first reaches zero,
+		// preferring the lowest label.
+		leave, next := -1, tmax
+		for i, lbl := range dict.Basic {
+			a := dict.A[i][p]
+			if a >= -eps {
+				continue
+			}
+			s := math.Max(t, -dict.B[i]/a)
+			if s < next || (s == next && leave >= 0 && lbl < dict.Basic[leave]) {
+				leave, next = i, s
+			}
+		}
+		if leave < 0 {
+			return pts, tmax
+		}
+
+		// Find the non-basic variable to enter which keeps the dictionary final,
+		// preferring the lowest label.
+		enter := -1
+		var min float64
+		for j, lbl := range dict.NonBasic[:p] {
+			a := dict.A[leave][j]
+			if a <= eps {
+				continue
+			}
+			r := math.Max(0, -dict.C[j]) / a
+			if enter < 0 || r < min || (r == min && lbl < dict.NonBasic[enter]) {
+				enter, min = j, r
+			}
+		}
+		if enter < 0 {
+			// The basic variable cannot be made non-negative beyond next.
+			return pts, next
+		}
+		dict = dict.Pivot(enter, leave)
+		t = next
+	}
+}
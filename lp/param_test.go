@@ -0,0 +1,139 @@
+package lp_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/jvlmdr/golp/lp"
+)
+
+func ExampleParamRHS() {
+	dict := new(lp.Dict)
+	dict.NonBasic = []int{0, 1}
+	dict.Basic = []int{2, 3}
+	// max_{x, y >= 0} 3 x + 2 y
+	dict.C = []float64{3, 2}
+	// subject to
+	// x + y <= 100 + t,  -x - y + 100 + t >= 0
+	// x <= 300,          -x + 300 >= 0
+	dict.A = [][]float64{{-1, -1}, {-1, 0}}
+	dict.B = []float64{100, 300}
+
+	pts, end, err := lp.ParamRHS(dict, []float64{1, 0}, 400)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+	for _, pt := range pts {
+		fmt.Printf("from %g: %g + %g (t - %g), basis %v\n", pt.T, pt.Obj, pt.Slope, pt.T, pt.Basis.Basic)
+	}
+	fmt.Println("until", end)
+	// Output:
+	// from 0: 300 + 3 (t - 0), basis [0 3]
+	// from 200: 900 + 2 (t - 200), basis [0 1]
+	// until 400
+}
+
+// Returns the optimal value of the problem with constants B + t dir
+// and objective C + t cdir.
+func solveAt(dict *lp.Dict, t float64, dir, cdir []float64) (float64, error) {
+	dict = dict.Clone()
+	for i := range dict.B {
+		dict.B[i] += t * dir[i]
+	}
+	for j := range dict.C {
+		dict.C[j] += t * cdir[j]
+	}
+	final, err := lp.Solve(dict)
+	if err != nil {
+		return 0, err
+	}
+	return final.Obj(), nil
+}
+
+func TestParam(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 200; trial++ {
+		m, n := 1+r.Intn(4), 1+r.Intn(4)
+		dict := randBoundedDict(r, m, n)
+		dir := make([]float64, m+n)
+		cdir := make([]float64, n)
+		obj := r.Intn(2) == 0
+		if obj {
+			for j := range cdir {
+				cdir[j] = r.NormFloat64()
+			}
+			// Keep the problem bounded by removing some upper bounds.
+			if r.Intn(2) == 0 {
+				dict, _ = dict.RemoveRow(dict.Basic[m+r.Intn(n)])
+				dir = dir[:len(dir)-1]
+			}
+		} else {
+			for i := range dir {
+				dir[i] = r.NormFloat64()
+			}
+		}
+		tmax := 5 * r.Float64()
+
+		var (
+			pts []lp.Breakpoint
+			end float64
+			err error
+		)
+		if obj {
+			pts, end, err = lp.ParamObj(dict, cdir, tmax)
+		} else {
+			pts, end, err = lp.ParamRHS(dict, dir, tmax)
+		}
+		_, wantErr := solveAt(dict, 0, dir, cdir)
+		if (err == nil) != (wantErr == nil) {
+			t.Fatalf("trial %d: got error %v, want %v", trial, err, wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if pts[0].T != 0 {
+			t.Fatalf("trial %d: first breakpoint at %g", trial, pts[0].T)
+		}
+
+		for k := 0; k <= 20; k++ {
+			s := tmax * float64(k) / 20
+			want, err := solveAt(dict, s, dir, cdir)
+			if s > end+1e-6 {
+				if err == nil {
+					t.Errorf("trial %d: t %g: solved beyond end %g", trial, s, end)
+				}
+				continue
+			}
+			if s > end-1e-6 {
+				continue
+			}
+			if err != nil {
+				t.Errorf("trial %d: t %g: %v", trial, s, err)
+				continue
+			}
+			i := len(pts) - 1
+			for pts[i].T > s {
+				i--
+			}
+			got := pts[i].Obj + pts[i].Slope*(s-pts[i].T)
+			if math.Abs(got-want) > 1e-6 {
+				t.Errorf("trial %d: t %g: got %g, want %g", trial, s, got, want)
+			}
+		}
+	}
+}
+
+func TestParam_dirLen(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	dict := randBoundedDict(r, 2, 3)
+	// The dictionary has 5 rows and 3 non-basic variables.
+	if _, _, err := lp.ParamRHS(dict, make([]float64, 3), 1); err == nil {
+		t.Error("ParamRHS: expected error")
+	}
+	if _, _, err := lp.ParamObj(dict, make([]float64, 5), 1); err == nil {
+		t.Error("ParamObj: expected error")
+	}
+}
@@ -0,0 +1,107 @@
+package lp
+
+// PhaseOne is a method for finding a feasible dictionary
+// when the initial dictionary is infeasible.
+type PhaseOne int
+
+const (
+	// The auxiliary problem minimizes a single auxiliary variable
+	// without regard to the objective (see SolveFeas).
+	AuxPhaseOne PhaseOne = iota
+	// The composite method maximizes the objective minus a weighted
+	// auxiliary variable, so that the basis found is close to optimal.
+	// The weight is increased by a factor of 10 from 1 to 1e6
+	// while the solution remains infeasible.
+	CompositePhaseOne
+	// The Big-M method maximizes the objective minus the auxiliary variable
+	// multiplied by a single large weight.
+	BigMPhaseOne
+)
+
+// Default weight of the auxiliary variable in the Big-M method.
+const defaultBigM = 1e6
+
+// Weights of the auxiliary variable in the composite method.
+var compositeWeights = []float64{1, 10, 100, 1e3, 1e4, 1e5, 1e6}
+
+// Finds a feasible dictionary of the original problem by maximizing
+//	obj - w u
+// subject to the constraints of the auxiliary problem
+// for each weight w in turn, where u is the auxiliary variable.
+// If u is still positive, the auxiliary problem is solved
+// from the current basis to determine whether the problem is infeasible.
+func solveFeasWeighted(orig *Dict, weights []float64, eps float64) (feas *Dict, infeas bool) {
+	dict := ToFeasDict(orig)
+	aux := numVars(dict) - 1
+	for _, w := range weights {
+		var unbnd bool
+		dict, unbnd = pivotFeasEps(withAuxObj(dict, orig, aux, w), eps)
+		if unbnd {
+			// The objective can not be used to determine feasibility.
+			break
+		}
+		if dict.auxValue(aux) <= eps {
+			return fromFeasEps(dict, orig, eps)
+		}
+	}
+
+	dict, _ = pivotFeasEps(withAuxObj(dict, nil, aux, 1), eps)
+	if -dict.Obj() > eps {
+		return nil, true
+	}
+	return fromFeasEps(dict, orig, eps)
+}
+
+// Returns a copy of the feasibility dictionary with the objective
+//	orig - w u
+// where u is the auxiliary variable.
+// The original objective is omitted if orig is nil.
+func withAuxObj(feas, orig *Dict, aux int, w float64) *Dict {
+	labels, coeffs, d := []int{aux}, []float64{-w}, 0.0
+	if orig != nil {
+		labels = append(labels, orig.NonBasic...)
+		coeffs = append(coeffs, orig.C...)
+		d = orig.D
+	}
+	dict := feas.Clone()
+	dict.C, dict.D = feas.express(labels, coeffs, d)
+	return dict
+}
+
+// Returns the value of the auxiliary variable in the solution of the dictionary.
+func (dict *Dict) auxValue(aux int) float64 {
+	if i, found := find(aux, dict.Basic); found {
+		return dict.B[i]
+	}
+	return 0
+}
+
+// Pivots a feasibility dictionary until it is final or unbounded
+// using NextFeasBlandEps.
+func pivotFeasEps(dict *Dict, eps float64) (final *Dict, unbnd bool) {
+	for {
+		piv := NextFeasBlandEps(dict, eps)
+		if piv.Unbounded {
+			return dict, true
+		}
+		if piv.Final {
+			return dict, false
+		}
+		dict = dict.Pivot(piv.Enter, piv.Leave)
+	}
+}
+
+// Finds a feasible dictionary using the given method.
+func solveFeasOpts(dict *Dict, opts Options, eps float64) (feas *Dict, infeas bool) {
+	switch opts.PhaseOne {
+	case CompositePhaseOne:
+		return solveFeasWeighted(dict, compositeWeights, eps)
+	case BigMPhaseOne:
+		bigM := opts.BigM
+		if bigM == 0 {
+			bigM = defaultBigM
+		}
+		return solveFeasWeighted(dict, []float64{bigM}, eps)
+	}
+	return solveFeasEps(dict, eps)
+}
@@ -0,0 +1,102 @@
+package lp_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/jvlmdr/golp/lp"
+)
+
+func TestSolveOpts_phaseOne(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	methods := []lp.PhaseOne{lp.CompositePhaseOne, lp.BigMPhaseOne}
+	var infeas, unbnd int
+	for trial := 0; trial < 300; trial++ {
+		// Initial dictionaries like those of the Colorado part 3 instances.
+		dict := randColoradoDict(r, 1+r.Intn(6), 1+r.Intn(6))
+		want, wantErr := lp.SolveOpts(dict, lp.Options{})
+		if wantErr != nil {
+			switch wantErr.Error() {
+			case "infeasible problem":
+				infeas++
+			case "unbounded problem":
+				unbnd++
+			}
+		}
+		for _, method := range methods {
+			got, err := lp.SolveOpts(dict, lp.Options{PhaseOne: method})
+			if (err == nil) != (wantErr == nil) || err != nil && err.Error() != wantErr.Error() {
+				t.Fatalf("trial %d: method %d: got error %v, want %v", trial, method, err, wantErr)
+			}
+			if err != nil {
+				continue
+			}
+			if math.Abs(got.Obj()-want.Obj()) > 1e-6*math.Max(1, math.Abs(want.Obj())) {
+				t.Errorf("trial %d: method %d: got objective %g, want %g", trial, method, got.Obj(), want.Obj())
+			}
+			if !got.FeasEps(1e-9) {
+				t.Errorf("trial %d: method %d: final dictionary infeasible", trial, method)
+			}
+		}
+	}
+	if infeas == 0 || unbnd == 0 {
+		t.Errorf("%d infeasible and %d unbounded problems, want some of each", infeas, unbnd)
+	}
+}
+
+func TestFromFeasDict_degenerate(t *testing.T) {
+	// max x0 + 2 x1 subject to x2 = 1 - x0 - x1 >= 0.
+	orig := lp.NewDict(1, 2)
+	orig.NonBasic = []int{0, 1}
+	orig.Basic = []int{2}
+	orig.A = [][]float64{{-1, -1}}
+	orig.B = []float64{1}
+	orig.C = []float64{1, 2}
+
+	// Final dictionaries of the feasibility problem
+	// with the auxiliary variable x3 basic.
+	feas := func(row []float64, b float64) *lp.Dict {
+		dict := lp.NewDict(1, 3)
+		dict.NonBasic = []int{0, 1, 2}
+		dict.Basic = []int{3}
+		dict.A = [][]float64{row}
+		dict.B = []float64{b}
+		return dict
+	}
+	cases := []struct {
+		feas *lp.Dict
+		want string
+		err  string
+	}{
+		// Pivot x3 = x0 - x2 to x0 = x3 + x2.
+		{feas: feas([]float64{1, 0, -1}, 0), want: "basic [0], non-basic [1 2], A [[0 1]], B [0], C [2 1]"},
+		// The row is redundant.
+		{feas: feas([]float64{0, 1e-12, 0}, 0), want: "basic [], non-basic [0 1 2], A [], B [], C [1 2 0]"},
+		{feas: feas([]float64{1, 0, -1}, 0.5), err: "auxiliary variable 3 is 0.5, not zero"},
+	}
+	for _, c := range cases {
+		dict, err := lp.FromFeasDict(c.feas, orig)
+		if c.err != "" {
+			if err == nil || err.Error() != c.err {
+				t.Errorf("want error %q, got %v", c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range dict.B {
+			// Print negative zero as zero.
+			dict.B[i] += 0
+			for j := range dict.A[i] {
+				dict.A[i][j] += 0
+			}
+		}
+		got := fmt.Sprintf("basic %v, non-basic %v, A %v, B %v, C %v", dict.Basic, dict.NonBasic, dict.A, dict.B, dict.C)
+		if got != c.want {
+			t.Errorf("got %s, want %s", got, c.want)
+		}
+	}
+}
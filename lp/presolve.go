@@ -0,0 +1,315 @@
+package lp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Presolved is a model which has been reduced by Presolve.
+// It records the reductions so that a solution of the reduced model
+// can be restored to a solution of the original model using Postsolve.
+type Presolved struct {
+	// Reduced model.
+	Model *Model
+	orig  *Model
+	// Index in the original model of each variable and constraint
+	// in the reduced model.
+	vars []int
+	cons []int
+	// Reductions in the order they were applied.
+	ops []reduction
+}
+
+type reductionKind int
+
+const (
+	// Constraint had no variables and was removed.
+	emptyRow reductionKind = iota
+	// Constraint had one variable and was replaced by its bounds.
+	singletonRow
+	// Constraint was a multiple of another constraint
+	// and was merged into it.
+	duplicateRow
+	// Variable had equal bounds and was substituted.
+	fixedVar
+)
+
+type reduction struct {
+	kind reductionKind
+	// Constraint which was removed.
+	con int
+	// Variable which was removed or whose bounds were changed.
+	v int
+	// For singleton rows, the coefficient of the variable.
+	// For duplicate rows, the ratio of the removed row to the kept row.
+	coeff float64
+	// Constraint which was kept in place of a duplicate.
+	keep int
+	// Whether the lower or upper bound of the variable (singleton)
+	// or the kept row (duplicate) was tightened.
+	lower, upper bool
+	// Value of a fixed variable.
+	val float64
+}
+
+// Presolve reduces a model by removing empty constraints,
+// replacing constraints of one variable with bounds,
+// merging constraints which are multiples of one another
+// and substituting variables whose bounds are equal.
+// Returns an error if the model is found to be infeasible.
+//
+// The model is not modified.
+func Presolve(m *Model) (*Presolved, error) {
+	return PresolveEps(m, DefaultEps)
+}
+
+func PresolveEps(m *Model, eps float64) (*Presolved, error) {
+	// Copy bounds which are modified.
+	vars := append([]Var(nil), m.Vars...)
+	cons := append([]Con(nil), m.Cons...)
+	delVar := make([]bool, len(vars))
+	delCon := make([]bool, len(cons))
+	constant := m.Const
+	var ops []reduction
+
+	for changed := true; changed; {
+		changed = false
+
+		for i := range cons {
+			if delCon[i] {
+				continue
+			}
+			con := &cons[i]
+			var (
+				count int
+				j     int
+				a     float64
+			)
+			for k, v := range con.Vars {
+				if delVar[v] || con.Coeffs[k] == 0 {
+					continue
+				}
+				count++
+				j, a = v, con.Coeffs[k]
+			}
+			switch count {
+			case 0:
+				if con.Lower > eps || con.Upper < -eps {
+					return nil, fmt.Errorf("constraint %d (%s) is empty but requires %g <= 0 <= %g", i, con.Name, con.Lower, con.Upper)
+				}
+				ops = append(ops, reduction{kind: emptyRow, con: i})
+			case 1:
+				lo, hi := con.Lower/a, con.Upper/a
+				if a < 0 {
+					lo, hi = hi, lo
+				}
+				op := reduction{kind: singletonRow, con: i, v: j, coeff: a}
+				if lo > vars[j].Lower {
+					vars[j].Lower, op.lower = lo, true
+				}
+				if hi < vars[j].Upper {
+					vars[j].Upper, op.upper = hi, true
+				}
+				if vars[j].Lower > vars[j].Upper+eps {
+					return nil, fmt.Errorf("constraint %d (%s) gives empty bounds %g <= x[%d] <= %g", i, con.Name, vars[j].Lower, j, vars[j].Upper)
+				}
+				ops = append(ops, op)
+			default:
+				continue
+			}
+			delCon[i] = true
+			changed = true
+		}
+
+		for j := range vars {
+			if delVar[j] {
+				continue
+			}
+			v := &vars[j]
+			if v.Lower > v.Upper+eps {
+				return nil, fmt.Errorf("variable %d (%s) has empty bounds %g <= x <= %g", j, v.Name, v.Lower, v.Upper)
+			}
+			if math.IsInf(v.Lower, 0) || v.Upper-v.Lower > eps {
+				continue
+			}
+			// Substitute fixed variable into constraints and objective.
+			val := v.Lower
+			for i := range cons {
+				if delCon[i] {
+					continue
+				}
+				con := &cons[i]
+				for k, u := range con.Vars {
+					if u == j {
+						con.Lower -= con.Coeffs[k] * val
+						con.Upper -= con.Coeffs[k] * val
+					}
+				}
+			}
+			constant += v.Obj * val
+			ops = append(ops, reduction{kind: fixedVar, v: j, val: val})
+			delVar[j] = true
+			changed = true
+		}
+
+		// Find constraints which are multiples of an earlier constraint.
+		first := make(map[string]int)
+		for i := range cons {
+			if delCon[i] {
+				continue
+			}
+			key, lead := rowKey(&cons[i], delVar)
+			if key == "" {
+				continue
+			}
+			k, dup := first[key]
+			if !dup {
+				first[key] = i
+				continue
+			}
+			// Row i is r times row k.
+			_, leadK := rowKey(&cons[k], delVar)
+			r := lead / leadK
+			lo, hi := cons[i].Lower/r, cons[i].Upper/r
+			if r < 0 {
+				lo, hi = hi, lo
+			}
+			op := reduction{kind: duplicateRow, con: i, keep: k, coeff: r}
+			if lo > cons[k].Lower {
+				cons[k].Lower, op.lower = lo, true
+			}
+			if hi < cons[k].Upper {
+				cons[k].Upper, op.upper = hi, true
+			}
+			if cons[k].Lower > cons[k].Upper+eps {
+				return nil, fmt.Errorf("constraints %d (%s) and %d (%s) are parallel and inconsistent", k, cons[k].Name, i, cons[i].Name)
+			}
+			ops = append(ops, op)
+			delCon[i] = true
+			changed = true
+		}
+	}
+
+	// Construct reduced model.
+	p := &Presolved{Model: NewModel(), orig: m, ops: ops}
+	p.Model.Const, p.Model.Min = constant, m.Min
+	index := make([]int, len(vars))
+	for j, v := range vars {
+		if delVar[j] {
+			continue
+		}
+		index[j] = len(p.vars)
+		p.vars = append(p.vars, j)
+		p.Model.Vars = append(p.Model.Vars, v)
+	}
+	for i, con := range cons {
+		if delCon[i] {
+			continue
+		}
+		red := Con{Name: con.Name, Lower: con.Lower, Upper: con.Upper}
+		for k, j := range con.Vars {
+			if delVar[j] || con.Coeffs[k] == 0 {
+				continue
+			}
+			red.Vars = append(red.Vars, index[j])
+			red.Coeffs = append(red.Coeffs, con.Coeffs[k])
+		}
+		p.cons = append(p.cons, i)
+		p.Model.Cons = append(p.Model.Cons, red)
+	}
+	return p, nil
+}
+
+// Returns a key which is equal for constraints which are multiples of each other
+// and the leading coefficient by which the constraint was normalized.
+// The key is empty for constraints with less than two variables.
+func rowKey(con *Con, delVar []bool) (string, float64) {
+	type entry struct {
+		v int
+		a float64
+	}
+	var entries []entry
+	for k, j := range con.Vars {
+		if !delVar[j] && con.Coeffs[k] != 0 {
+			entries = append(entries, entry{j, con.Coeffs[k]})
+		}
+	}
+	if len(entries) < 2 {
+		// Empty and singleton rows are handled separately.
+		return "", 0
+	}
+	sort.Slice(entries, func(s, t int) bool { return entries[s].v < entries[t].v })
+	lead := entries[0].a
+	var b strings.Builder
+	for _, e := range entries {
+		b.WriteString(strconv.Itoa(e.v))
+		b.WriteByte(':')
+		b.WriteString(strconv.FormatFloat(e.a/lead, 'g', 12, 64))
+		b.WriteByte(' ')
+	}
+	return b.String(), lead
+}
+
+// Postsolve restores a solution of the reduced model
+// to a solution of the original model, including duals and reduced costs.
+func (p *Presolved) Postsolve(red *ModelSoln) *ModelSoln {
+	m := p.orig
+	s := &ModelSoln{
+		X:        make([]float64, len(m.Vars)),
+		Duals:    make([]float64, len(m.Cons)),
+		RedCosts: make([]float64, len(m.Vars)),
+	}
+	for k, j := range p.vars {
+		s.X[j] = red.X[k]
+		s.RedCosts[j] = red.RedCosts[k]
+	}
+	for k, i := range p.cons {
+		s.Duals[i] = red.Duals[k]
+	}
+
+	// Returns true if the dual value is due to the upper side
+	// of a constraint or bound.
+	upperSide := func(y float64) bool { return (y > 0) != m.Min }
+
+	// Undo reductions in reverse order.
+	for t := len(p.ops) - 1; t >= 0; t-- {
+		op := p.ops[t]
+		switch op.kind {
+		case emptyRow:
+			// Dual is zero.
+		case singletonRow:
+			// If the variable is at a bound which came from the constraint,
+			// transfer its reduced cost to the dual of the constraint.
+			d := s.RedCosts[op.v]
+			if d != 0 && (upperSide(d) && op.upper || !upperSide(d) && op.lower) {
+				s.Duals[op.con] = d / op.coeff
+				s.RedCosts[op.v] = 0
+			}
+		case duplicateRow:
+			// If the kept constraint is at a bound which came from the duplicate,
+			// transfer its dual to the duplicate.
+			y := s.Duals[op.keep]
+			if y != 0 && (upperSide(y) && op.upper || !upperSide(y) && op.lower) {
+				s.Duals[op.con] = y / op.coeff
+				s.Duals[op.keep] = 0
+			}
+		case fixedVar:
+			s.X[op.v] = op.val
+			// Reduced cost from the constraints present when the variable was fixed.
+			d := m.Vars[op.v].Obj
+			for i, con := range m.Cons {
+				for k, j := range con.Vars {
+					if j == op.v {
+						d -= s.Duals[i] * con.Coeffs[k]
+					}
+				}
+			}
+			s.RedCosts[op.v] = d
+		}
+	}
+	s.Obj = m.Obj(s.X)
+	return s
+}
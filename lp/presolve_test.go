@@ -0,0 +1,158 @@
+package lp_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/jvlmdr/golp/lp"
+)
+
+func ExamplePresolve() {
+	inf := math.Inf(1)
+	m := lp.NewModel()
+	// max_{x, y, z >= 0} x + y + z
+	x := m.AddVar("x", 1, 0, inf)
+	y := m.AddVar("y", 1, 0, inf)
+	z := m.AddVar("z", 1, 2, 2)
+	// subject to
+	m.AddCon("empty", 0, 1, nil, nil)
+	m.AddCon("bound", math.Inf(-1), 6, []int{x}, []float64{2})
+	m.AddCon("pair", math.Inf(-1), 10, []int{x, y, z}, []float64{1, 2, 1})
+	m.AddCon("double", math.Inf(-1), 16, []int{x, y, z}, []float64{2, 4, 2})
+
+	p, err := lp.Presolve(m)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+	fmt.Printf("reduced to %d variables, %d constraints\n", len(p.Model.Vars), len(p.Model.Cons))
+	red, err := lp.SolveModel(p.Model)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+	s := p.Postsolve(red)
+	fmt.Printf("%.6g at %.6g\n", s.Obj, s.X)
+	fmt.Printf("duals %.6g\n", s.Duals)
+	// Output:
+	// reduced to 2 variables, 1 constraints
+	// 6.5 at [3 1.5 2]
+	// duals [0 0.25 0 0.25]
+}
+
+// Returns a random bounded model which is feasible at the lower bounds
+// and contains constraints which can be presolved.
+func randModel(r *rand.Rand, m, n int) *lp.Model {
+	inf := math.Inf(1)
+	model := lp.NewModel()
+	model.Min = r.Intn(2) == 0
+	x := make([]float64, n)
+	for j := 0; j < n; j++ {
+		lo := float64(r.Intn(3))
+		hi := lo + float64(r.Intn(5))
+		model.AddVar("", float64(r.Intn(11)-5), lo, hi)
+		x[j] = lo
+	}
+	for i := 0; i < m; i++ {
+		var con lp.Con
+		for j := 0; j < n; j++ {
+			if r.Intn(2) == 0 {
+				con.Vars = append(con.Vars, j)
+				con.Coeffs = append(con.Coeffs, float64(r.Intn(7)-3))
+			}
+		}
+		act := con.Activity(x)
+		lo, hi := act-float64(r.Intn(5)), act+float64(r.Intn(5))
+		if r.Intn(2) == 0 {
+			lo = math.Inf(-1)
+		}
+		model.AddCon("", lo, hi, con.Vars, con.Coeffs)
+		if r.Intn(3) == 0 {
+			// Add a multiple of the constraint.
+			k := float64(r.Intn(3) + 1)
+			model.AddCon("", math.Inf(-1), k*hi+float64(r.Intn(2)), con.Vars, scale(k, con.Coeffs))
+		}
+	}
+	// Add an upper bound as a constraint.
+	j := r.Intn(n)
+	model.AddCon("", math.Inf(-1), 2*(x[j]+float64(r.Intn(3))), []int{j}, []float64{2})
+	model.AddCon("", -1, inf, nil, nil)
+	return model
+}
+
+func scale(k float64, x []float64) []float64 {
+	y := make([]float64, len(x))
+	for i := range x {
+		y[i] = k * x[i]
+	}
+	return y
+}
+
+// Checks that the duals and reduced costs are consistent
+// and give the same objective as the primal.
+func checkDuals(t *testing.T, m *lp.Model, s *lp.ModelSoln, eps float64) {
+	upper := func(y float64) bool { return (y > 0) != m.Min }
+	dual := m.Const
+	for i, con := range m.Cons {
+		y := s.Duals[i]
+		if y == 0 {
+			continue
+		}
+		if upper(y) {
+			dual += y * con.Upper
+		} else {
+			dual += y * con.Lower
+		}
+	}
+	for j, v := range m.Vars {
+		d := v.Obj
+		for i, con := range m.Cons {
+			for k, u := range con.Vars {
+				if u == j {
+					d -= s.Duals[i] * con.Coeffs[k]
+				}
+			}
+		}
+		if math.Abs(d-s.RedCosts[j]) > eps {
+			t.Errorf("reduced cost %d: got %g, want %g", j, s.RedCosts[j], d)
+		}
+		if d == 0 {
+			continue
+		}
+		if upper(d) {
+			dual += d * v.Upper
+		} else {
+			dual += d * v.Lower
+		}
+	}
+	if math.Abs(dual-s.Obj) > eps {
+		t.Errorf("dual objective: got %g, want %g", dual, s.Obj)
+	}
+}
+
+func TestPostsolve(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 100; trial++ {
+		m := randModel(r, 4, 4)
+		want, err := lp.SolveModel(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, err := lp.Presolve(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		red, err := lp.SolveModel(p.Model)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := p.Postsolve(red)
+		if math.Abs(got.Obj-want.Obj) > 1e-6 {
+			t.Errorf("trial %d: objective: got %g, want %g", trial, got.Obj, want.Obj)
+		}
+		checkDuals(t, m, want, 1e-6)
+		checkDuals(t, m, got, 1e-6)
+	}
+}
@@ -0,0 +1,150 @@
+package lp
+
+import (
+	"math"
+	"sort"
+)
+
+// Scaling is a method for choosing the scale of each variable.
+type Scaling int
+
+const (
+	// Variables are not scaled.
+	NoScaling Scaling = iota
+	// Geometric scaling repeatedly divides each row and column
+	// by the geometric mean of its largest and smallest coefficients.
+	GeometricScaling
+	// Equilibration divides each row and then each column
+	// by its largest coefficient.
+	EquilibrationScaling
+)
+
+// Number of passes of geometric scaling.
+const geomPasses = 4
+
+// Scale returns a dictionary in terms of scaled variables
+//	x'[k] = x[k] / s[k]
+// and the scale s of each variable in increasing order of label (see Labels).
+// Scale factors are powers of two so that scaling does not introduce rounding errors.
+//
+// Basic variables (rows) and non-basic variables (columns) are scaled
+// such that the coefficients in A are close to one in magnitude.
+// The objective value is unchanged.
+// Use Unscale to recover a dictionary of the original problem.
+func Scale(dict *Dict, method Scaling) (scaled *Dict, s []float64) {
+	m, n := len(dict.Basic), len(dict.NonBasic)
+	// Row factors multiply rows, column factors multiply columns.
+	row := ones(m)
+	col := ones(n)
+
+	switch method {
+	case GeometricScaling:
+		for pass := 0; pass < geomPasses; pass++ {
+			for i := range row {
+				lo, hi := rangeAbs(n, func(j int) float64 { return dict.A[i][j] * col[j] })
+				if hi > 0 {
+					row[i] = 1 / math.Sqrt(lo*hi)
+				}
+			}
+			for j := range col {
+				lo, hi := rangeAbs(m, func(i int) float64 { return row[i] * dict.A[i][j] })
+				if hi > 0 {
+					col[j] = 1 / math.Sqrt(lo*hi)
+				}
+			}
+		}
+	case EquilibrationScaling:
+		for i := range row {
+			_, hi := rangeAbs(n, func(j int) float64 { return dict.A[i][j] })
+			if hi > 0 {
+				row[i] = 1 / hi
+			}
+		}
+		for j := range col {
+			_, hi := rangeAbs(m, func(i int) float64 { return row[i] * dict.A[i][j] })
+			if hi > 0 {
+				col[j] = 1 / hi
+			}
+		}
+	}
+
+	// Row i is multiplied by 1 / s[Basic[i]].
+	labels := dict.Labels()
+	s = make([]float64, len(labels))
+	for i, lbl := range dict.Basic {
+		s[sort.SearchInts(labels, lbl)] = pow2(1 / row[i])
+	}
+	for j, lbl := range dict.NonBasic {
+		s[sort.SearchInts(labels, lbl)] = pow2(col[j])
+	}
+	return rescale(dict, s), s
+}
+
+// Unscale returns the dictionary in terms of the original variables
+// given a dictionary in terms of the scaled variables from Scale.
+// The dictionary may have been pivoted since it was scaled
+// but must have the same labels.
+func Unscale(dict *Dict, s []float64) *Dict {
+	inv := make([]float64, len(s))
+	for k := range s {
+		inv[k] = 1 / s[k]
+	}
+	return rescale(dict, inv)
+}
+
+// Returns the dictionary in terms of x'[k] = x[k] / s[k]
+// where s is in increasing order of label.
+func rescale(src *Dict, s []float64) *Dict {
+	labels := src.Labels()
+	rows := make([]float64, len(src.Basic))
+	for i, lbl := range src.Basic {
+		rows[i] = s[sort.SearchInts(labels, lbl)]
+	}
+	cols := make([]float64, len(src.NonBasic))
+	for j, lbl := range src.NonBasic {
+		cols[j] = s[sort.SearchInts(labels, lbl)]
+	}
+
+	dst := src.Clone()
+	for i := range src.Basic {
+		dst.B[i] = src.B[i] / rows[i]
+		for j := range src.NonBasic {
+			dst.A[i][j] = src.A[i][j] * cols[j] / rows[i]
+		}
+	}
+	for j := range src.NonBasic {
+		dst.C[j] = src.C[j] * cols[j]
+	}
+	return dst
+}
+
+// Returns the smallest and largest non-zero magnitudes of f(0), ..., f(n-1).
+// Returns zero if all values are zero.
+func rangeAbs(n int, f func(int) float64) (lo, hi float64) {
+	for k := 0; k < n; k++ {
+		v := math.Abs(f(k))
+		if v == 0 {
+			continue
+		}
+		if hi == 0 || v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+	return lo, hi
+}
+
+// Returns the power of two nearest to x.
+func pow2(x float64) float64 {
+	return math.Exp2(math.Round(math.Log2(x)))
+}
+
+func ones(n int) []float64 {
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = 1
+	}
+	return x
+}
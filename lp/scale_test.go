@@ -0,0 +1,82 @@
+package lp_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/jvlmdr/golp/lp"
+)
+
+func ExampleSolveOpts() {
+	dict := new(lp.Dict)
+	dict.NonBasic = []int{0, 1}
+	dict.Basic = []int{2, 3, 4}
+	// max_{x, y >= 0} 1e2 x + 2e6 y
+	dict.C = []float64{1e2, 2e6}
+	// subject to
+	// -1e-2 x + 1e2 y <= 1e2
+	//  3e2 x + 2e6 y <= 1.2e7
+	//  2e-2 x + 3e2 y <= 1.2e3
+	dict.A = make([][]float64, 3)
+	dict.B = make([]float64, 3)
+	dict.A[0], dict.B[0] = []float64{1e-2, -1e2}, 1e2
+	dict.A[1], dict.B[1] = []float64{-3e2, -2e6}, 1.2e7
+	dict.A[2], dict.B[2] = []float64{-2e-2, -3e2}, 1.2e3
+
+	dict, err := lp.SolveOpts(dict, lp.Options{Scale: lp.GeometricScaling})
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+	fmt.Printf("%.6g at %.6g\n", dict.Obj(), dict.Soln()[:2])
+	// Output:
+	// 7.4e+06 at [18000 2.8]
+}
+
+func TestSolveOpts_scale(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 50; trial++ {
+		dict := randIntDict(r, 4, 3)
+		// Scale rows and columns badly.
+		for i := range dict.A {
+			k := math.Pow(10, float64(r.Intn(9)-4))
+			dict.B[i] *= k
+			for j := range dict.A[i] {
+				dict.A[i][j] *= k
+			}
+		}
+		for j := range dict.C {
+			k := math.Pow(10, float64(r.Intn(9)-4))
+			dict.C[j] *= k
+			for i := range dict.A {
+				dict.A[i][j] *= k
+			}
+		}
+		want, err := lp.Solve(dict)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, method := range []lp.Scaling{lp.GeometricScaling, lp.EquilibrationScaling} {
+			got, err := lp.SolveOpts(dict, lp.Options{Scale: method})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(got.Obj()-want.Obj()) > 1e-6*math.Max(1, math.Abs(want.Obj())) {
+				t.Errorf("trial %d, scaling %d: objective: got %g, want %g", trial, method, got.Obj(), want.Obj())
+			}
+			// Check solution is feasible for the original problem.
+			x := got.Soln()
+			for i, lbl := range dict.Basic {
+				v := dict.B[i]
+				for j, nb := range dict.NonBasic {
+					v += dict.A[i][j] * x[nb]
+				}
+				if math.Abs(v-x[lbl]) > 1e-6*math.Max(1, math.Abs(dict.B[i])) || v < -1e-6 {
+					t.Errorf("trial %d, scaling %d: row %d: got %g, want %g", trial, method, i, x[lbl], v)
+				}
+			}
+		}
+	}
+}
@@ -3,39 +3,121 @@ package lp
 import "fmt"
 
 // Solve solves a linear program.
+// Returns an error if the dictionary is not valid (see Validate).
 func Solve(dict *Dict) (final *Dict, err error) {
 	return SolveEps(dict, DefaultEps)
 }
 
 func SolveEps(dict *Dict, eps float64) (final *Dict, err error) {
+	return solveValidEps(dict, Options{}, eps)
+}
+
+// Solves a valid dictionary.
+func solveOptsEps(dict *Dict, opts Options, eps float64) (final *Dict, err error) {
+	if opts.Basis != nil {
+		dict = dict.ToBasisEps(opts.Basis, eps)
+		if !dict.Feas() && dict.Dual().Feas() {
+			var infeas bool
+			dict, infeas = pivotToFinalDualEps(dict, eps)
+			if infeas {
+				return nil, fmt.Errorf("infeasible problem")
+			}
+		}
+	}
 	if !dict.Feas() {
 		// If the solution associated with the dictionary is infeasible,
 		// attempt find a feasible dictionary.
 		var infeas bool
-		dict, infeas = SolveFeasEps(dict, eps)
+		dict, infeas = solveFeasOpts(dict, opts, eps)
 		if infeas {
 			return nil, fmt.Errorf("infeasible problem")
 		}
 	}
 	var unbnd bool
-	dict, unbnd = PivotToFinalEps(dict, eps)
+	dict, unbnd = pivotToFinalEps(dict, eps)
 	if unbnd {
 		return nil, fmt.Errorf("unbounded problem")
 	}
 	return dict, nil
 }
 
+// Options controls the solution of a linear program.
+type Options struct {
+	// Tolerance for feasibility and pivoting.
+	// If zero, DefaultEps is used.
+	Eps float64
+	// Scaling of the variables before solving.
+	// The final dictionary is in terms of the original variables.
+	Scale Scaling
+	// Method for finding a feasible dictionary
+	// if the initial dictionary is infeasible.
+	PhaseOne PhaseOne
+	// Weight of the auxiliary variable in the Big-M method.
+	// If zero, 1e6 is used.
+	BigM float64
+	// Basis from which to start, typically the basis of
+	// the final dictionary of a similar problem.
+	// The dictionary is first factorized in this basis (see ToBasis).
+	// If it is then primal feasible, the primal simplex method continues.
+	// If it is dual feasible, the dual simplex method is used.
+	// Otherwise a feasible dictionary is found using PhaseOne.
+	// If nil, the solver starts from the given dictionary.
+	Basis *Basis
+}
+
+// SolveOpts solves a linear program with the given options.
+func SolveOpts(dict *Dict, opts Options) (final *Dict, err error) {
+	eps := opts.Eps
+	if eps == 0 {
+		eps = DefaultEps
+	}
+	return solveValidEps(dict, opts, eps)
+}
+
+// Validates the dictionary and solves it in terms of dense labels.
+func solveValidEps(dict *Dict, opts Options, eps float64) (final *Dict, err error) {
+	if err := dict.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid dictionary: %v", err)
+	}
+	dict, labels := dict.dense()
+	if opts.Basis != nil {
+		opts.Basis = denseBasis(opts.Basis, labels)
+	}
+	if opts.Scale == NoScaling {
+		final, err = solveOptsEps(dict, opts, eps)
+	} else {
+		scaled, s := Scale(dict, opts.Scale)
+		final, err = solveOptsEps(scaled, opts, eps)
+		if err == nil {
+			final = Unscale(final, s)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sparse(final, labels), nil
+}
+
 // PivotToFinal carries a feasible dictionary to solution.
-// Assumes that initial dictionary is feasible.
-func PivotToFinal(dict *Dict) (final *Dict, unbnd bool) {
+// Returns an error if the dictionary is not valid (see Validate)
+// or is not feasible.
+func PivotToFinal(dict *Dict) (final *Dict, unbnd bool, err error) {
 	return PivotToFinalEps(dict, DefaultEps)
 }
 
-func PivotToFinalEps(dict *Dict, eps float64) (final *Dict, unbnd bool) {
-	if !dict.Feas() {
-		panic("initial dictionary infeasible")
+func PivotToFinalEps(dict *Dict, eps float64) (final *Dict, unbnd bool, err error) {
+	if err := dict.Validate(); err != nil {
+		return nil, false, fmt.Errorf("invalid dictionary: %v", err)
+	}
+	if !dict.FeasEps(eps) {
+		return nil, false, fmt.Errorf("initial dictionary is infeasible")
 	}
+	final, unbnd = pivotToFinalEps(dict, eps)
+	return final, unbnd, nil
+}
 
+// Carries a valid dictionary which is feasible within eps to solution.
+func pivotToFinalEps(dict *Dict, eps float64) (final *Dict, unbnd bool) {
 	// Pivot until reaching the solution.
 	var iter int
 	for {
@@ -55,30 +137,31 @@ func PivotToFinalEps(dict *Dict, eps float64) (final *Dict, unbnd bool) {
 // SolveFeas solves the feasibility problem of a given dictionary.
 // If feasible, returns a feasible dictionary of the original problem.
 // Assumes that the original dictionary is infeasible.
-func SolveFeas(dict *Dict) (final *Dict, infeas bool) {
+// Returns an error if the dictionary is not valid (see Validate).
+func SolveFeas(dict *Dict) (final *Dict, infeas bool, err error) {
 	return SolveFeasEps(dict, DefaultEps)
 }
 
-func SolveFeasEps(orig *Dict, eps float64) (feas *Dict, infeas bool) {
+func SolveFeasEps(dict *Dict, eps float64) (feas *Dict, infeas bool, err error) {
+	if err := dict.Validate(); err != nil {
+		return nil, false, fmt.Errorf("invalid dictionary: %v", err)
+	}
+	feas, infeas = solveFeasEps(dict, eps)
+	return feas, infeas, nil
+}
+
+// Solves the feasibility problem of a valid dictionary.
+func solveFeasEps(orig *Dict, eps float64) (feas *Dict, infeas bool) {
 	// Transform to a dictionary for the feasibility problem.
 	dict := ToFeasDict(orig)
 
 	// Perform feasibility pivots.
-	var iter int
-	for {
-		piv := NextFeasBlandEps(dict, eps)
-		if piv.Unbounded {
-			// Auxiliary problem
-			//   min  x  s.t.  x >= 0, ...
-			// is always bounded.
-			panic("unbounded")
-		}
-		if piv.Final {
-			break
-		}
-		dict = dict.Pivot(piv.Enter, piv.Leave)
-		iter++
-		//log.Printf("%4d  f:%10.3e\n", iter, dict.Obj())
+	dict, unbnd := pivotFeasEps(dict, eps)
+	if unbnd {
+		// Auxiliary problem
+		//   min  x  s.t.  x >= 0, ...
+		// is always bounded.
+		panic("unbounded")
 	}
 
 	// The gap to feasibility such that (A x - u 1 <= b).
@@ -88,5 +171,5 @@ func SolveFeasEps(orig *Dict, eps float64) (feas *Dict, infeas bool) {
 	}
 
 	// Transform back to a feasible dictionary for the original problem.
-	return FromFeasDict(dict, orig), false
+	return fromFeasEps(dict, orig, eps)
 }
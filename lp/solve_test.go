@@ -2,6 +2,9 @@ package lp_test
 
 import (
 	"fmt"
+	"math"
+	"math/rand"
+	"testing"
 
 	"github.com/jvlmdr/golp/lp"
 )
@@ -31,3 +34,152 @@ func ExampleSolve() {
 	// Output:
 	// 7.4 at [1.8 2.8]
 }
+
+func TestSolve_invalid(t *testing.T) {
+	valid := func() *lp.Dict {
+		dict := lp.NewDict(2, 2)
+		dict.Basic = []int{2, 3}
+		dict.NonBasic = []int{0, 1}
+		dict.A = [][]float64{{-1, -1}, {-1, 1}}
+		dict.B = []float64{4, -1}
+		dict.C = []float64{1, 2}
+		return dict
+	}
+	cases := []struct {
+		modify func(*lp.Dict)
+		err    string
+	}{
+		{func(d *lp.Dict) { d.B = d.B[:1] }, "B has length 1, want 2 basic variables"},
+		{func(d *lp.Dict) { d.A[1] = append(d.A[1], 0) }, "row 1 of A has length 3, want 2 non-basic variables"},
+		{func(d *lp.Dict) { d.C = nil }, "C has length 0, want 2 non-basic variables"},
+		{func(d *lp.Dict) { d.NonBasic[1] = 3 }, "label 3 of non-basic variable 1 is repeated"},
+		{func(d *lp.Dict) { d.A[0][1] = math.NaN() }, "A[0][1] is NaN"},
+		{func(d *lp.Dict) { d.D = math.Inf(-1) }, "D is -Inf"},
+	}
+	if _, err := lp.Solve(valid()); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range cases {
+		dict := valid()
+		c.modify(dict)
+		want := "invalid dictionary: " + c.err
+		if _, err := lp.Solve(dict); err == nil || err.Error() != want {
+			t.Errorf("Solve: want error %q, got %v", want, err)
+		}
+		if _, err := lp.SolveIntOpts(dict, lp.IntOptions{}); err == nil || err.Error() != want {
+			t.Errorf("SolveIntOpts: want error %q, got %v", want, err)
+		}
+		if _, _, err := lp.SolveFeas(dict); err == nil || err.Error() != want {
+			t.Errorf("SolveFeas: want error %q, got %v", want, err)
+		}
+		if _, _, err := lp.PivotToFinal(dict); err == nil || err.Error() != want {
+			t.Errorf("PivotToFinal: want error %q, got %v", want, err)
+		}
+	}
+
+	// The initial dictionary is valid but infeasible.
+	want := "initial dictionary is infeasible"
+	if _, _, err := lp.PivotToFinal(valid()); err == nil || err.Error() != want {
+		t.Errorf("PivotToFinal: want error %q, got %v", want, err)
+	}
+	feas, infeas, err := lp.SolveFeas(valid())
+	if err != nil || infeas {
+		t.Fatalf("SolveFeas: got infeasible %v, error %v", infeas, err)
+	}
+	if _, _, err := lp.PivotToFinal(feas); err != nil {
+		t.Errorf("PivotToFinal: %v", err)
+	}
+}
+
+// Returns a copy of the dictionary with every label replaced by f(label).
+func relabel(dict *lp.Dict, f func(int) int) *lp.Dict {
+	dst := dict.Clone()
+	for i, lbl := range dst.Basic {
+		dst.Basic[i] = f(lbl)
+	}
+	for j, lbl := range dst.NonBasic {
+		dst.NonBasic[j] = f(lbl)
+	}
+	return dst
+}
+
+func TestSolve_labels(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 100; trial++ {
+		// Colorado dictionaries are labelled from 1.
+		dict := randColoradoDict(r, 1+r.Intn(5), 1+r.Intn(5))
+		want, wantErr := lp.Solve(relabel(dict, func(lbl int) int { return lbl - 1 }))
+		// Sparse, negative and large labels which preserve the order.
+		sparse := relabel(dict, func(lbl int) int { return 7*lbl + 3 })
+		large := relabel(dict, func(lbl int) int { return (lbl - 3) * 100000000 })
+		for _, d := range []*lp.Dict{dict, sparse, large} {
+			got, err := lp.Solve(d)
+			if (err == nil) != (wantErr == nil) {
+				t.Fatalf("labels %v: got error %v, want %v", d.NonBasic, err, wantErr)
+			}
+			if err != nil {
+				continue
+			}
+			if got.Obj() != want.Obj() {
+				t.Fatalf("labels %v: got objective %g, want %g", d.NonBasic, got.Obj(), want.Obj())
+			}
+			if x, y := got.Soln(), want.Soln(); !equalSoln(x, y) {
+				t.Fatalf("labels %v: got solution %g, want %g", d.NonBasic, x, y)
+			}
+		}
+
+		// Solvers which do not relabel the dictionary.
+		if !large.Feas() {
+			feas, infeas, err := lp.SolveFeas(large)
+			if err != nil {
+				t.Fatalf("SolveFeas: %v", err)
+			}
+			_, wantInfeas, _ := lp.SolveFeas(dict)
+			if infeas != wantInfeas {
+				t.Fatalf("SolveFeas: got infeasible %v, want %v", infeas, wantInfeas)
+			}
+			if !infeas && !feas.Feas() {
+				t.Fatalf("SolveFeas: dictionary is not feasible")
+			}
+			if aux := lp.ToFeasDict(large); len(aux.NonBasic) != len(large.NonBasic)+1 {
+				t.Fatalf("ToFeasDict: got %d non-basic variables, want %d", len(aux.NonBasic), len(large.NonBasic)+1)
+			}
+		}
+		if cut := lp.CutPlane(large); len(cut.Labels()) != len(lp.CutPlane(dict).Labels()) {
+			t.Fatalf("CutPlane: got %d variables, want %d", len(cut.Labels()), len(lp.CutPlane(dict).Labels()))
+		}
+
+		intDict := randIntDict(r, 1+r.Intn(3), 1+r.Intn(3))
+		want, wantErr = lp.SolveInt(intDict)
+		for _, f := range []func(int) int{
+			func(lbl int) int { return 5*lbl + 1 },
+			func(lbl int) int { return (lbl - 2) * 100000000 },
+		} {
+			got, err := lp.SolveInt(relabel(intDict, f))
+			if (err == nil) != (wantErr == nil) {
+				t.Fatalf("integer: got error %v, want %v", err, wantErr)
+			}
+			if err != nil {
+				continue
+			}
+			if got.Obj() != want.Obj() {
+				t.Fatalf("integer: got objective %g, want %g", got.Obj(), want.Obj())
+			}
+			if x, y := got.Soln()[:len(intDict.Labels())], want.Soln()[:len(intDict.Labels())]; !equalSoln(x, y) {
+				t.Fatalf("integer: got solution %g, want %g", x, y)
+			}
+		}
+	}
+}
+
+func equalSoln(x, y []float64) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if math.Abs(x[i]-y[i]) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package transport
+
+import (
+	"fmt"
+	"math"
+)
+
+// Assignment is the solution of an assignment problem.
+type Assignment struct {
+	// Column assigned to each row.
+	Cols []int
+	Cost float64
+	// Potentials of the rows and columns.
+	// The reduced cost of each cell
+	//	Cost[i][j] - U[i] - V[j]
+	// is non-negative and is zero for assigned cells.
+	// The potentials of the columns are non-positive.
+	U, V []float64
+}
+
+// Assign assigns each row to a distinct column with minimum total cost
+// using the Hungarian algorithm.
+// The number of rows must not exceed the number of columns.
+// Rows are added one at a time and each is assigned
+// along a shortest augmenting path of reduced costs,
+// adjusting the potentials so that reduced costs remain non-negative.
+func Assign(cost [][]float64) (*Assignment, error) {
+	n := len(cost)
+	var m int
+	if n > 0 {
+		m = len(cost[0])
+	}
+	if n > m {
+		return nil, fmt.Errorf("%d rows exceed %d columns", n, m)
+	}
+	for i, row := range cost {
+		if len(row) != m {
+			return nil, fmt.Errorf("row %d of cost has length %d, want %d", i, len(row), m)
+		}
+		for j, c := range row {
+			if math.IsNaN(c) || math.IsInf(c, 0) {
+				return nil, fmt.Errorf("cost[%d][%d] is %g", i, j, c)
+			}
+		}
+	}
+
+	// Rows and columns are indexed from 1.
+	// Column 0 is a dummy column to which the new row is assigned.
+	u := make([]float64, n+1)
+	v := make([]float64, m+1)
+	// Row assigned to each column, or 0.
+	p := make([]int, m+1)
+	// Previous column on the augmenting path.
+	way := make([]int, m+1)
+	for i := 1; i <= n; i++ {
+		p[0] = i
+		j0 := 0
+		minv := make([]float64, m+1)
+		for j := range minv {
+			minv[j] = math.Inf(1)
+		}
+		used := make([]bool, m+1)
+		for p[j0] != 0 {
+			used[j0] = true
+			i0 := p[j0]
+			delta, j1 := math.Inf(1), 0
+			for j := 1; j <= m; j++ {
+				if used[j] {
+					continue
+				}
+				if cur := cost[i0-1][j-1] - u[i0] - v[j]; cur < minv[j] {
+					minv[j], way[j] = cur, j0
+				}
+				if minv[j] < delta {
+					delta, j1 = minv[j], j
+				}
+			}
+			for j := 0; j <= m; j++ {
+				if used[j] {
+					u[p[j]] += delta
+					v[j] -= delta
+				} else {
+					minv[j] -= delta
+				}
+			}
+			j0 = j1
+		}
+		// Augment along the path.
+		for j0 != 0 {
+			j1 := way[j0]
+			p[j0] = p[j1]
+			j0 = j1
+		}
+	}
+
+	a := &Assignment{
+		Cols: make([]int, n),
+		U:    u[1:],
+		V:    v[1:],
+	}
+	for j := 1; j <= m; j++ {
+		if p[j] != 0 {
+			a.Cols[p[j]-1] = j - 1
+		}
+	}
+	for i, j := range a.Cols {
+		a.Cost += cost[i][j]
+	}
+	return a, nil
+}
@@ -0,0 +1,81 @@
+package transport_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/jvlmdr/golp/lp"
+	"github.com/jvlmdr/golp/lp/transport"
+)
+
+func ExampleAssign() {
+	cost := [][]float64{
+		{4, 1, 3},
+		{2, 0, 5},
+		{3, 2, 2},
+	}
+	a, err := transport.Assign(cost)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+	fmt.Println(a.Cost, a.Cols)
+	// Output:
+	// 5 [1 0 2]
+}
+
+func TestAssign(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 300; trial++ {
+		n := 1 + r.Intn(5)
+		m := n + r.Intn(3)
+		cost := randCost(r, n, m)
+		// Assignment is transportation with unit supplies
+		// and a dummy row which takes the unassigned columns.
+		supply := make([]float64, n+1)
+		demand := make([]float64, m)
+		for i := range supply {
+			supply[i] = 1
+		}
+		supply[n] = float64(m - n)
+		for j := range demand {
+			demand[j] = 1
+		}
+		want, err := lp.SolveModel(transportModel(supply, demand, append(cost, make([]float64, m))))
+		if err != nil {
+			t.Fatalf("trial %d: %v", trial, err)
+		}
+
+		a, err := transport.Assign(cost)
+		if err != nil {
+			t.Fatalf("trial %d: %v", trial, err)
+		}
+		if math.Abs(a.Cost-want.Obj) > 1e-6 {
+			t.Errorf("trial %d: got cost %g, want %g", trial, a.Cost, want.Obj)
+		}
+		used := make(map[int]bool)
+		for i, j := range a.Cols {
+			if used[j] {
+				t.Errorf("trial %d: column %d assigned twice", trial, j)
+			}
+			used[j] = true
+			if red := cost[i][j] - a.U[i] - a.V[j]; math.Abs(red) > 1e-9 {
+				t.Errorf("trial %d: assigned cell (%d, %d) has reduced cost %g", trial, i, j, red)
+			}
+		}
+		for i := range cost {
+			for j := range cost[i] {
+				if red := cost[i][j] - a.U[i] - a.V[j]; red < -1e-9 {
+					t.Errorf("trial %d: cell (%d, %d) has reduced cost %g", trial, i, j, red)
+				}
+			}
+		}
+		for j, v := range a.V {
+			if v > 1e-9 {
+				t.Errorf("trial %d: column %d has potential %g", trial, j, v)
+			}
+		}
+	}
+}
@@ -0,0 +1,366 @@
+// Package transport solves transportation and assignment problems.
+//
+// The transportation problem is
+//	min sum_ij Cost[i][j] x[i][j]
+//	s.t. sum_j x[i][j] = Supply[i]
+//	     sum_i x[i][j] = Demand[j]
+//	     x[i][j] >= 0.
+// If the supplies and demands are integers,
+// then the solution is integral.
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/jvlmdr/golp/lp"
+)
+
+// Relative tolerance for the difference between the total supply and demand.
+const balanceTol = 1e-9
+
+// Method chooses the initial basis of the transportation simplex.
+type Method int
+
+const (
+	// NorthwestCorner allocates to the first row and column
+	// which have remaining supply and demand.
+	NorthwestCorner Method = iota
+	// Vogel allocates to the cheapest cell of the row or column
+	// with the greatest difference between its two cheapest cells.
+	Vogel
+)
+
+// Solution is the solution of a transportation problem.
+type Solution struct {
+	X    [][]float64
+	Cost float64
+	// Potentials of the rows and columns.
+	// The reduced cost of each cell
+	//	Cost[i][j] - U[i] - V[j]
+	// is non-negative and is zero for basic cells.
+	U, V []float64
+}
+
+// A cell of the basis.
+type cell struct{ i, j int }
+
+// Transportation simplex state.
+// Rows are nodes 0, ..., m-1 and columns are nodes m, ..., m+n-1
+// of a spanning tree whose edges are the basic cells.
+type simplex struct {
+	cost  [][]float64
+	m, n  int
+	x     [][]float64
+	basic [][]bool
+	u, v  []float64
+	eps   float64
+}
+
+// Solve solves a balanced transportation problem
+// using the transportation simplex method.
+// The initial basis is found by the given method.
+// The potentials of the basis are found by the MODI method (u-v method)
+// and the entering cell is chosen by Bland's rule:
+// the first cell in row-major order with reduced cost
+// less than -lp.DefaultEps.
+// Returns an error if the total supply and demand differ
+// by more than a relative tolerance.
+func Solve(supply, demand []float64, cost [][]float64, method Method) (*Solution, error) {
+	return SolveEps(supply, demand, cost, method, lp.DefaultEps)
+}
+
+func SolveEps(supply, demand []float64, cost [][]float64, method Method, eps float64) (*Solution, error) {
+	if err := check(supply, demand, cost); err != nil {
+		return nil, err
+	}
+	m, n := len(supply), len(demand)
+	s := &simplex{
+		cost:  cost,
+		m:     m,
+		n:     n,
+		x:     make([][]float64, m),
+		basic: make([][]bool, m),
+		u:     make([]float64, m),
+		v:     make([]float64, n),
+		eps:   eps,
+	}
+	for i := range s.x {
+		s.x[i] = make([]float64, n)
+		s.basic[i] = make([]bool, n)
+	}
+	if m > 0 && n > 0 {
+		s.initial(supply, demand, method)
+		for {
+			s.potentials()
+			i, j, found := s.entering()
+			if !found {
+				break
+			}
+			s.pivot(i, j)
+		}
+	}
+
+	sol := &Solution{X: s.x, U: s.u, V: s.v}
+	for i := range cost {
+		for j, c := range cost[i] {
+			sol.Cost += c * s.x[i][j]
+		}
+	}
+	return sol, nil
+}
+
+// Returns an error if the problem is not valid or not balanced.
+func check(supply, demand []float64, cost [][]float64) error {
+	if len(cost) != len(supply) {
+		return fmt.Errorf("cost has %d rows, want %d supplies", len(cost), len(supply))
+	}
+	for i, row := range cost {
+		if len(row) != len(demand) {
+			return fmt.Errorf("row %d of cost has length %d, want %d demands", i, len(row), len(demand))
+		}
+		for j, c := range row {
+			if math.IsNaN(c) || math.IsInf(c, 0) {
+				return fmt.Errorf("cost[%d][%d] is %g", i, j, c)
+			}
+		}
+	}
+	var total, sum float64
+	for i, b := range supply {
+		if !(b >= 0) || math.IsInf(b, 1) {
+			return fmt.Errorf("supply %d is %g", i, b)
+		}
+		total += b
+		sum += b
+	}
+	for j, d := range demand {
+		if !(d >= 0) || math.IsInf(d, 1) {
+			return fmt.Errorf("demand %d is %g", j, d)
+		}
+		total -= d
+		sum += d
+	}
+	if math.Abs(total) > balanceTol*math.Max(sum, 1) {
+		return errors.New("total supply and demand differ")
+	}
+	return nil
+}
+
+// Finds an initial basis of m+n-1 cells.
+// Each allocation exhausts the supply of a row or the demand of a column,
+// which is then removed, until one row or column remains.
+func (s *simplex) initial(supply, demand []float64, method Method) {
+	supply = append([]float64(nil), supply...)
+	demand = append([]float64(nil), demand...)
+	rows := make([]bool, s.m)
+	cols := make([]bool, s.n)
+	for i := range rows {
+		rows[i] = true
+	}
+	for j := range cols {
+		cols[j] = true
+	}
+	numRows, numCols := s.m, s.n
+	for numRows+numCols > 1 {
+		var i, j int
+		if method == Vogel {
+			i, j = s.vogel(rows, cols)
+		} else {
+			i, j = first(rows), first(cols)
+		}
+		x := math.Min(supply[i], demand[j])
+		s.x[i][j], s.basic[i][j] = x, true
+		supply[i] -= x
+		demand[j] -= x
+		if supply[i] == 0 && numRows > 1 {
+			rows[i] = false
+			numRows--
+		} else {
+			cols[j] = false
+			numCols--
+		}
+	}
+}
+
+// Returns the index of the first true element.
+func first(active []bool) int {
+	for i, a := range active {
+		if a {
+			return i
+		}
+	}
+	return -1
+}
+
+// Returns the cheapest cell of the active row or column
+// with the greatest penalty, preferring rows and lower indices.
+// The penalty is the difference between the two cheapest cells,
+// or the cost of the cell if there is only one.
+func (s *simplex) vogel(rows, cols []bool) (int, int) {
+	var (
+		best   = math.Inf(-1)
+		bi, bj int
+	)
+	for i, a := range rows {
+		if !a {
+			continue
+		}
+		min1, min2, arg := math.Inf(1), math.Inf(1), -1
+		for j, b := range cols {
+			if !b {
+				continue
+			}
+			if c := s.cost[i][j]; c < min1 {
+				min1, min2, arg = c, min1, j
+			} else if c < min2 {
+				min2 = c
+			}
+		}
+		if arg < 0 {
+			continue
+		}
+		p := min2 - min1
+		if math.IsInf(min2, 1) {
+			p = min1
+		}
+		if p > best {
+			best, bi, bj = p, i, arg
+		}
+	}
+	for j, b := range cols {
+		if !b {
+			continue
+		}
+		min1, min2, arg := math.Inf(1), math.Inf(1), -1
+		for i, a := range rows {
+			if !a {
+				continue
+			}
+			if c := s.cost[i][j]; c < min1 {
+				min1, min2, arg = c, min1, i
+			} else if c < min2 {
+				min2 = c
+			}
+		}
+		if arg < 0 {
+			continue
+		}
+		p := min2 - min1
+		if math.IsInf(min2, 1) {
+			p = min1
+		}
+		if p > best {
+			best, bi, bj = p, arg, j
+		}
+	}
+	return bi, bj
+}
+
+// Computes potentials such that the reduced cost of every basic cell is zero
+// by traversing the spanning tree from the first row, with U[0] = 0.
+func (s *simplex) potentials() {
+	done := make([]bool, s.m+s.n)
+	done[0] = true
+	s.u[0] = 0
+	stack := []int{0}
+	for len(stack) > 0 {
+		k := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if k < s.m {
+			i := k
+			for j := 0; j < s.n; j++ {
+				if s.basic[i][j] && !done[s.m+j] {
+					s.v[j] = s.cost[i][j] - s.u[i]
+					done[s.m+j] = true
+					stack = append(stack, s.m+j)
+				}
+			}
+		} else {
+			j := k - s.m
+			for i := 0; i < s.m; i++ {
+				if s.basic[i][j] && !done[i] {
+					s.u[i] = s.cost[i][j] - s.v[j]
+					done[i] = true
+					stack = append(stack, i)
+				}
+			}
+		}
+	}
+}
+
+// Returns the first non-basic cell with reduced cost less than -eps.
+func (s *simplex) entering() (int, int, bool) {
+	for i := 0; i < s.m; i++ {
+		for j := 0; j < s.n; j++ {
+			if !s.basic[i][j] && s.cost[i][j]-s.u[i]-s.v[j] < -s.eps {
+				return i, j, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// Moves flow around the cycle formed by the entering cell and the basis.
+func (s *simplex) pivot(i, j int) {
+	// Find the path from column j to row i in the tree.
+	prev := make([]int, s.m+s.n)
+	for k := range prev {
+		prev[k] = -1
+	}
+	start := s.m + j
+	prev[start] = start
+	queue := []int{start}
+	for len(queue) > 0 && prev[i] < 0 {
+		k := queue[0]
+		queue = queue[1:]
+		for l := 0; l < s.m+s.n; l++ {
+			if prev[l] >= 0 || (k < s.m) == (l < s.m) {
+				continue
+			}
+			r, c := k, l-s.m
+			if k >= s.m {
+				r, c = l, k-s.m
+			}
+			if s.basic[r][c] {
+				prev[l] = k
+				queue = append(queue, l)
+			}
+		}
+	}
+	// Cells of the cycle after the entering cell,
+	// which alternately lose and gain flow.
+	var path []cell
+	for k := i; k != start; k = prev[k] {
+		l := prev[k]
+		if k < s.m {
+			path = append(path, cell{k, l - s.m})
+		} else {
+			path = append(path, cell{l, k - s.m})
+		}
+	}
+
+	// Leaving cell has the least flow among those which lose flow,
+	// preferring the first in row-major order.
+	leave := -1
+	delta := math.Inf(1)
+	for t := 0; t < len(path); t += 2 {
+		c := path[t]
+		x := s.x[c.i][c.j]
+		if x < delta || (x == delta && c.i*s.n+c.j < path[leave].i*s.n+path[leave].j) {
+			leave, delta = t, x
+		}
+	}
+	s.x[i][j] += delta
+	for t, c := range path {
+		if t%2 == 0 {
+			s.x[c.i][c.j] -= delta
+		} else {
+			s.x[c.i][c.j] += delta
+		}
+	}
+	s.basic[i][j] = true
+	c := path[leave]
+	s.basic[c.i][c.j] = false
+	s.x[c.i][c.j] = 0
+}
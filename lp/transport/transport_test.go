@@ -0,0 +1,127 @@
+package transport_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/jvlmdr/golp/lp"
+	"github.com/jvlmdr/golp/lp/transport"
+)
+
+func ExampleSolve() {
+	supply := []float64{20, 30, 25}
+	demand := []float64{10, 35, 30}
+	cost := [][]float64{
+		{8, 6, 10},
+		{9, 12, 13},
+		{14, 9, 16},
+	}
+	s, err := transport.Solve(supply, demand, cost, transport.Vogel)
+	if err != nil {
+		fmt.Print(err)
+		return
+	}
+	fmt.Println(s.Cost, s.X)
+	// Output:
+	// 735 [[0 10 10] [10 0 20] [0 25 0]]
+}
+
+// Returns the transportation problem as a linear program.
+func transportModel(supply, demand []float64, cost [][]float64) *lp.Model {
+	m := lp.NewModel()
+	m.Min = true
+	for _, b := range supply {
+		m.AddCon("", b, b, nil, nil)
+	}
+	for _, d := range demand {
+		m.AddCon("", d, d, nil, nil)
+	}
+	for i := range supply {
+		for j := range demand {
+			k := m.AddVar("", cost[i][j], 0, math.Inf(1))
+			row, col := &m.Cons[i], &m.Cons[len(supply)+j]
+			row.Vars, row.Coeffs = append(row.Vars, k), append(row.Coeffs, 1)
+			col.Vars, col.Coeffs = append(col.Vars, k), append(col.Coeffs, 1)
+		}
+	}
+	return m
+}
+
+func randCost(r *rand.Rand, m, n int) [][]float64 {
+	cost := make([][]float64, m)
+	for i := range cost {
+		cost[i] = make([]float64, n)
+		for j := range cost[i] {
+			cost[i][j] = float64(r.Intn(21) - 5)
+		}
+	}
+	return cost
+}
+
+func TestSolve(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 300; trial++ {
+		m, n := 1+r.Intn(5), 1+r.Intn(5)
+		supply := make([]float64, m)
+		demand := make([]float64, n)
+		// Integer supplies and demands with equal totals,
+		// often with degenerate partial sums.
+		for k := r.Intn(6 * (m + n)); k > 0; k-- {
+			supply[r.Intn(m)]++
+			demand[r.Intn(n)]++
+		}
+		cost := randCost(r, m, n)
+		want, err := lp.SolveModel(transportModel(supply, demand, cost))
+		if err != nil {
+			t.Fatalf("trial %d: %v", trial, err)
+		}
+
+		for _, method := range []transport.Method{transport.NorthwestCorner, transport.Vogel} {
+			s, err := transport.Solve(supply, demand, cost, method)
+			if err != nil {
+				t.Fatalf("trial %d: method %d: %v", trial, method, err)
+			}
+			if math.Abs(s.Cost-want.Obj) > 1e-6 {
+				t.Errorf("trial %d: method %d: got cost %g, want %g", trial, method, s.Cost, want.Obj)
+			}
+			rows := make([]float64, m)
+			cols := make([]float64, n)
+			for i := range s.X {
+				for j, x := range s.X[i] {
+					if x < 0 || x != math.Floor(x) {
+						t.Errorf("trial %d: method %d: x[%d][%d] = %g", trial, method, i, j, x)
+					}
+					if red := cost[i][j] - s.U[i] - s.V[j]; red < -1e-9 || (x > 0 && red > 1e-9) {
+						t.Errorf("trial %d: method %d: x[%d][%d] = %g with reduced cost %g", trial, method, i, j, x, red)
+					}
+					rows[i] += x
+					cols[j] += x
+				}
+			}
+			if fmt.Sprint(rows) != fmt.Sprint(supply) || fmt.Sprint(cols) != fmt.Sprint(demand) {
+				t.Errorf("trial %d: method %d: got totals %v %v, want %v %v", trial, method, rows, cols, supply, demand)
+			}
+		}
+	}
+}
+
+func TestSolve_unbalanced(t *testing.T) {
+	_, err := transport.Solve([]float64{1, 2}, []float64{4}, [][]float64{{1}, {2}}, transport.Vogel)
+	if err == nil {
+		t.Fatal("no error for unbalanced problem")
+	}
+}
+
+func TestSolve_fractional(t *testing.T) {
+	// The totals are not exactly equal in floating point.
+	supply, demand := []float64{0.1, 0.2}, []float64{0.3}
+	sol, err := transport.Solve(supply, demand, [][]float64{{1}, {2}}, transport.Vogel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 0.5; math.Abs(sol.Cost-want) > 1e-9 {
+		t.Errorf("got cost %g, want %g", sol.Cost, want)
+	}
+}
@@ -0,0 +1,72 @@
+package lp
+
+import (
+	"fmt"
+	"math"
+)
+
+// Validate returns an error describing the first problem found
+// if the dictionary is not well formed.
+// The lengths of A, B and C must agree with the numbers of
+// basic and non-basic variables, the labels must be distinct
+// and all coefficients must be finite.
+func (dict *Dict) Validate() error {
+	m, n := len(dict.Basic), len(dict.NonBasic)
+	if len(dict.B) != m {
+		return fmt.Errorf("B has length %d, want %d basic variables", len(dict.B), m)
+	}
+	if len(dict.A) != m {
+		return fmt.Errorf("A has %d rows, want %d basic variables", len(dict.A), m)
+	}
+	for i, ai := range dict.A {
+		if len(ai) != n {
+			return fmt.Errorf("row %d of A has length %d, want %d non-basic variables", i, len(ai), n)
+		}
+	}
+	if len(dict.C) != n {
+		return fmt.Errorf("C has length %d, want %d non-basic variables", len(dict.C), n)
+	}
+
+	seen := make(map[int]bool, m+n)
+	check := func(set string, k, lbl int) error {
+		if seen[lbl] {
+			return fmt.Errorf("label %d of %s variable %d is repeated", lbl, set, k)
+		}
+		seen[lbl] = true
+		return nil
+	}
+	for i, lbl := range dict.Basic {
+		if err := check("basic", i, lbl); err != nil {
+			return err
+		}
+	}
+	for j, lbl := range dict.NonBasic {
+		if err := check("non-basic", j, lbl); err != nil {
+			return err
+		}
+	}
+
+	for i, ai := range dict.A {
+		for j, aij := range ai {
+			if !isFinite(aij) {
+				return fmt.Errorf("A[%d][%d] is %v", i, j, aij)
+			}
+		}
+		if !isFinite(dict.B[i]) {
+			return fmt.Errorf("B[%d] is %v", i, dict.B[i])
+		}
+	}
+	for j, cj := range dict.C {
+		if !isFinite(cj) {
+			return fmt.Errorf("C[%d] is %v", j, cj)
+		}
+	}
+	if !isFinite(dict.D) {
+		return fmt.Errorf("D is %v", dict.D)
+	}
+	return nil
+}
+
+func isFinite(x float64) bool {
+	return !math.IsNaN(x) && !math.IsInf(x, 0)
+}